@@ -0,0 +1,226 @@
+package defenv
+
+import "time"
+
+// BoolAny extracts bool value from the first present environment variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func BoolAny(names []string, defaultValue bool, opts ...Option) bool {
+	return std.BoolAny(names, defaultValue, opts...)
+}
+
+// BoolAnyStrict extracts bool value from the first present environment variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func BoolAnyStrict(names []string, defaultValue bool, opts ...Option) (bool, error) {
+	return std.BoolAnyStrict(names, defaultValue, opts...)
+}
+
+// DurationAny extracts time.Duration value from the first present environment variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func DurationAny(names []string, defaultValue time.Duration, opts ...Option) time.Duration {
+	return std.DurationAny(names, defaultValue, opts...)
+}
+
+// DurationAnyStrict extracts time.Duration value from the first present environment variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func DurationAnyStrict(names []string, defaultValue time.Duration, opts ...Option) (time.Duration, error) {
+	return std.DurationAnyStrict(names, defaultValue, opts...)
+}
+
+// Float64Any extracts float64 value from the first present environment variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func Float64Any(names []string, defaultValue float64, opts ...Option) float64 {
+	return std.Float64Any(names, defaultValue, opts...)
+}
+
+// Float64AnyStrict extracts float64 value from the first present environment variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func Float64AnyStrict(names []string, defaultValue float64, opts ...Option) (float64, error) {
+	return std.Float64AnyStrict(names, defaultValue, opts...)
+}
+
+// IntAny extracts int value from the first present environment variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func IntAny(names []string, defaultValue int, opts ...Option) int {
+	return std.IntAny(names, defaultValue, opts...)
+}
+
+// IntAnyStrict extracts int value from the first present environment variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func IntAnyStrict(names []string, defaultValue int, opts ...Option) (int, error) {
+	return std.IntAnyStrict(names, defaultValue, opts...)
+}
+
+// Int64Any extracts int64 value from the first present environment variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func Int64Any(names []string, defaultValue int64, opts ...Option) int64 {
+	return std.Int64Any(names, defaultValue, opts...)
+}
+
+// Int64AnyStrict extracts int64 value from the first present environment variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func Int64AnyStrict(names []string, defaultValue int64, opts ...Option) (int64, error) {
+	return std.Int64AnyStrict(names, defaultValue, opts...)
+}
+
+// StringAny extracts string value from the first present environment variable of names
+// and returns defaultValue if none of them is present
+func StringAny(names []string, defaultValue string, opts ...Option) string {
+	return std.StringAny(names, defaultValue, opts...)
+}
+
+// UintAny extracts uint value from the first present environment variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func UintAny(names []string, defaultValue uint, opts ...Option) uint {
+	return std.UintAny(names, defaultValue, opts...)
+}
+
+// UintAnyStrict extracts uint value from the first present environment variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func UintAnyStrict(names []string, defaultValue uint, opts ...Option) (uint, error) {
+	return std.UintAnyStrict(names, defaultValue, opts...)
+}
+
+// Uint64Any extracts uint64 value from the first present environment variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func Uint64Any(names []string, defaultValue uint64, opts ...Option) uint64 {
+	return std.Uint64Any(names, defaultValue, opts...)
+}
+
+// Uint64AnyStrict extracts uint64 value from the first present environment variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func Uint64AnyStrict(names []string, defaultValue uint64, opts ...Option) (uint64, error) {
+	return std.Uint64AnyStrict(names, defaultValue, opts...)
+}
+
+// BoolAny extracts bool value from the first present variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func (e *Env) BoolAny(names []string, defaultValue bool, opts ...Option) bool {
+	name, opts := anyName(names, opts)
+	return e.Bool(name, defaultValue, opts...)
+}
+
+// BoolAnyStrict extracts bool value from the first present variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) BoolAnyStrict(names []string, defaultValue bool, opts ...Option) (bool, error) {
+	name, opts := anyName(names, opts)
+	return e.BoolStrict(name, defaultValue, opts...)
+}
+
+// DurationAny extracts time.Duration value from the first present variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func (e *Env) DurationAny(names []string, defaultValue time.Duration, opts ...Option) time.Duration {
+	name, opts := anyName(names, opts)
+	return e.Duration(name, defaultValue, opts...)
+}
+
+// DurationAnyStrict extracts time.Duration value from the first present variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) DurationAnyStrict(names []string, defaultValue time.Duration, opts ...Option) (time.Duration, error) {
+	name, opts := anyName(names, opts)
+	return e.DurationStrict(name, defaultValue, opts...)
+}
+
+// Float64Any extracts float64 value from the first present variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func (e *Env) Float64Any(names []string, defaultValue float64, opts ...Option) float64 {
+	name, opts := anyName(names, opts)
+	return e.Float64(name, defaultValue, opts...)
+}
+
+// Float64AnyStrict extracts float64 value from the first present variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) Float64AnyStrict(names []string, defaultValue float64, opts ...Option) (float64, error) {
+	name, opts := anyName(names, opts)
+	return e.Float64Strict(name, defaultValue, opts...)
+}
+
+// IntAny extracts int value from the first present variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func (e *Env) IntAny(names []string, defaultValue int, opts ...Option) int {
+	name, opts := anyName(names, opts)
+	return e.Int(name, defaultValue, opts...)
+}
+
+// IntAnyStrict extracts int value from the first present variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) IntAnyStrict(names []string, defaultValue int, opts ...Option) (int, error) {
+	name, opts := anyName(names, opts)
+	return e.IntStrict(name, defaultValue, opts...)
+}
+
+// Int64Any extracts int64 value from the first present variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func (e *Env) Int64Any(names []string, defaultValue int64, opts ...Option) int64 {
+	name, opts := anyName(names, opts)
+	return e.Int64(name, defaultValue, opts...)
+}
+
+// Int64AnyStrict extracts int64 value from the first present variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) Int64AnyStrict(names []string, defaultValue int64, opts ...Option) (int64, error) {
+	name, opts := anyName(names, opts)
+	return e.Int64Strict(name, defaultValue, opts...)
+}
+
+// StringAny extracts string value from the first present variable of names
+// and returns defaultValue if none of them is present
+func (e *Env) StringAny(names []string, defaultValue string, opts ...Option) string {
+	name, opts := anyName(names, opts)
+	return e.String(name, defaultValue, opts...)
+}
+
+// UintAny extracts uint value from the first present variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func (e *Env) UintAny(names []string, defaultValue uint, opts ...Option) uint {
+	name, opts := anyName(names, opts)
+	return e.Uint(name, defaultValue, opts...)
+}
+
+// UintAnyStrict extracts uint value from the first present variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) UintAnyStrict(names []string, defaultValue uint, opts ...Option) (uint, error) {
+	name, opts := anyName(names, opts)
+	return e.UintStrict(name, defaultValue, opts...)
+}
+
+// Uint64Any extracts uint64 value from the first present variable of names
+// and returns defaultValue if none of them is present or the value can not be parsed
+func (e *Env) Uint64Any(names []string, defaultValue uint64, opts ...Option) uint64 {
+	name, opts := anyName(names, opts)
+	return e.Uint64(name, defaultValue, opts...)
+}
+
+// Uint64AnyStrict extracts uint64 value from the first present variable of names
+// and returns defaultValue if none of them is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) Uint64AnyStrict(names []string, defaultValue uint64, opts ...Option) (uint64, error) {
+	name, opts := anyName(names, opts)
+	return e.Uint64Strict(name, defaultValue, opts...)
+}
+
+// anyName returns the first of names and opts with the other names
+// added by Fallback, so getters reading the first present variable
+// of names work the same way as getters with the Fallback option
+func anyName(names []string, opts []Option) (string, []Option) {
+	switch len(names) {
+	case 0:
+		return "", opts
+	case 1:
+		return names[0], opts
+	}
+
+	return names[0], withOptions(opts, Fallback(names[1:]...))
+}
@@ -0,0 +1,132 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAny(t *testing.T) {
+	names := []string{"NEW_NAME", "OLD_NAME"}
+
+	for _, tc := range []struct {
+		name   string
+		src    MapSource
+		get    func(env *Env) (interface{}, error)
+		expRes interface{}
+		expErr error
+	}{
+		{
+			name: `value of the first name`,
+			src:  MapSource{"NEW_NAME": "new", "OLD_NAME": "old"},
+			get: func(env *Env) (interface{}, error) {
+				return env.StringAny(names, "default"), nil
+			},
+			expRes: "new",
+		},
+		{
+			name: `value of the second name then the first is absent`,
+			src:  MapSource{"OLD_NAME": "old"},
+			get: func(env *Env) (interface{}, error) {
+				return env.StringAny(names, "default"), nil
+			},
+			expRes: "old",
+		},
+		{
+			name: `use default value then all names are absent`,
+			src:  MapSource{},
+			get: func(env *Env) (interface{}, error) {
+				return env.StringAny(names, "default"), nil
+			},
+			expRes: "default",
+		},
+		{
+			name: `empty value of the first name is used`,
+			src:  MapSource{"NEW_NAME": "", "OLD_NAME": "old"},
+			get: func(env *Env) (interface{}, error) {
+				return env.StringAny(names, "default"), nil
+			},
+			expRes: "",
+		},
+		{
+			name: `use default value then the first present value can not be parsed`,
+			src:  MapSource{"NEW_NAME": "bad", "OLD_NAME": "8"},
+			get: func(env *Env) (interface{}, error) {
+				return env.IntAny(names, 4), nil
+			},
+			expRes: 4,
+		},
+		{
+			name: `int from the second name`,
+			src:  MapSource{"OLD_NAME": "8"},
+			get: func(env *Env) (interface{}, error) {
+				return env.IntAnyStrict(names, 4)
+			},
+			expRes: 8,
+		},
+		{
+			name: `fail then the first present value can not be parsed`,
+			src:  MapSource{"NEW_NAME": "bad", "OLD_NAME": "8"},
+			get: func(env *Env) (interface{}, error) {
+				return env.IntAnyStrict(names, 4)
+			},
+			expErr: errors.New(`defenv: parse NEW_NAME="bad" as int: invalid syntax`),
+		},
+		{
+			name: `duration from the first name`,
+			src:  MapSource{"NEW_NAME": "1m"},
+			get: func(env *Env) (interface{}, error) {
+				return env.DurationAnyStrict(names, time.Second)
+			},
+			expRes: time.Minute,
+		},
+		{
+			name: `use default value then there are no names`,
+			src:  MapSource{"NEW_NAME": "true"},
+			get: func(env *Env) (interface{}, error) {
+				return env.BoolAnyStrict(nil, false)
+			},
+			expRes: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.get(NewEnv(tc.src))
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if tc.expErr == nil && res != tc.expRes {
+				t.Errorf("expected value: %v, got: %v", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestStringAny(t *testing.T) {
+	defer func() {
+		if err := os.Unsetenv("OLD_NAME"); err != nil {
+			t.Errorf("coudn't unset OLD_NAME: %s", err)
+		}
+	}()
+
+	if err := os.Setenv("OLD_NAME", "old"); err != nil {
+		t.Fatal(err)
+	}
+
+	res := StringAny([]string{"NEW_NAME", "OLD_NAME"}, "default")
+	if res != "old" {
+		t.Errorf("expected value: %s, got: %s", "old", res)
+	}
+}
+
+func TestAnyWithoutNames(t *testing.T) {
+	env := NewEnv(MapSource{"PORT": "80"})
+
+	if res := env.IntAny([]string{}, 8080, Required()); res != 8080 {
+		t.Errorf("expected value: %d, got: %d", 8080, res)
+	}
+	if _, err := env.IntAnyStrict(nil, 8080, Required()); !errors.Is(err, ErrNotSet) {
+		t.Errorf("expected error wrapping: %v, got: %v", ErrNotSet, err)
+	}
+}
@@ -0,0 +1,309 @@
+package defenv
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CachedSource is a Source memoizing lookups of another Source.
+// Concurrent lookups of a variable which is not remembered share
+// a single lookup of the other Source. It is safe for concurrent use
+type CachedSource struct {
+	src Source
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	calls   map[string]*cacheCall
+}
+
+// cacheCall is a lookup of the underlying Source in progress,
+// done is closed once its result is set
+type cacheCall struct {
+	done chan struct{}
+	val  string
+	ok   bool
+	err  error
+}
+
+type cacheEntry struct {
+	val     string
+	ok      bool
+	expires time.Time
+}
+
+// Cached returns a Source that remembers results of src lookups, including
+// absent variables, for ttl. If ttl is not positive, results are kept
+// until Invalidate is called
+func Cached(src Source, ttl time.Duration) *CachedSource {
+	return &CachedSource{
+		src:     src,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]cacheEntry{},
+		calls:   map[string]*cacheCall{},
+	}
+}
+
+// Lookup returns value of variable named name and reports whether it is present
+func (c *CachedSource) Lookup(name string) (string, bool) {
+	val, ok, _ := c.lookup(name, func() (string, bool, error) {
+		val, ok := c.src.Lookup(name)
+		return val, ok, nil
+	})
+
+	return val, ok
+}
+
+// LookupContext is like Lookup, but passes ctx to the underlying Source
+// if it implements SourceContext. Failed lookups are not remembered,
+// their error is returned to all callers waiting for the lookup
+func (c *CachedSource) LookupContext(ctx context.Context, name string) (string, bool, error) {
+	src, ok := c.src.(SourceContext)
+	if !ok {
+		val, ok := c.Lookup(name)
+		return val, ok, nil
+	}
+
+	return c.lookup(name, func() (string, bool, error) {
+		return src.LookupContext(ctx, name)
+	})
+}
+
+func (c *CachedSource) lookup(name string, fetch func() (string, bool, error)) (string, bool, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	entry, found := c.entries[name]
+	if found && (c.ttl <= 0 || now.Before(entry.expires)) {
+		c.mu.Unlock()
+		return entry.val, entry.ok, nil
+	}
+
+	if call, ok := c.calls[name]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.val, call.ok, call.err
+	}
+
+	call := &cacheCall{done: make(chan struct{})}
+	c.calls[name] = call
+	c.mu.Unlock()
+
+	call.val, call.ok, call.err = fetch()
+	if call.err != nil {
+		call.val, call.ok = "", false
+	}
+
+	c.mu.Lock()
+	// the call is not remembered if Invalidate was called meanwhile
+	if c.calls[name] == call {
+		delete(c.calls, name)
+		if call.err == nil {
+			c.entries[name] = cacheEntry{val: call.val, ok: call.ok, expires: now.Add(c.ttl)}
+		}
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.val, call.ok, call.err
+}
+
+// Names returns names of variables of the underlying Source if it
+// implements Enumerator. Names are not remembered
+func (c *CachedSource) Names() ([]string, error) {
+	src, ok := c.src.(Enumerator)
+	if !ok {
+		return nil, nil
+	}
+
+	return src.Names()
+}
+
+// Invalidate drops all remembered lookups
+func (c *CachedSource) Invalidate() {
+	c.mu.Lock()
+	c.entries = map[string]cacheEntry{}
+	c.calls = map[string]*cacheCall{}
+	c.mu.Unlock()
+}
+
+// WithCache returns a copy of the Env remembering resolved and parsed values
+// of variables, so hot paths reading configuration on every request do not
+// pay for lookups and parsing repeatedly. The cache is shared by copies of
+// the returned Env. Changes of variables are not seen until ResetCache is called
+func (e *Env) WithCache() *Env {
+	c := *e
+	c.cache = newValueCache()
+	return &c
+}
+
+// ResetCache drops values remembered by an Env returned by WithCache
+// and its copies, so the next reads see current values of variables
+func (e *Env) ResetCache() {
+	if e.cache == nil {
+		return
+	}
+
+	e.cache.mu.Lock()
+	e.cache.resolved = map[resolveKey]resolvedValue{}
+	e.cache.parsed = map[parseKey]parsedValue{}
+	e.cache.mu.Unlock()
+}
+
+// valueCache remembers resolved and parsed values of variables
+type valueCache struct {
+	mu       sync.RWMutex
+	resolved map[resolveKey]resolvedValue
+	parsed   map[parseKey]parsedValue
+}
+
+// resolveKey identifies a lookup, copies of an Env sharing the cache
+// may transform values differently
+type resolveKey struct {
+	name         string
+	fileFallback bool
+	trimSpace    bool
+	unquote      bool
+	emptyAsUnset bool
+}
+
+type resolvedValue struct {
+	val string
+	ok  bool
+	src Source
+}
+
+type parseKey struct {
+	typ      string
+	raw      string
+	literals bool
+}
+
+// parsedValue is a result of parsing, only the field of the parsed type is set
+type parsedValue struct {
+	b   bool
+	i   int64 // int, int64 and time.Duration values
+	u   uint64
+	f   float64
+	err error
+}
+
+func newValueCache() *valueCache {
+	return &valueCache{resolved: map[resolveKey]resolvedValue{}, parsed: map[parseKey]parsedValue{}}
+}
+
+// resolve is like lookupName, but uses the cache if the Env has one.
+// Failed lookups are not remembered
+func (e *Env) resolve(name string) (string, bool, Source, error) {
+	if e.cache == nil {
+		return e.lookupName(name)
+	}
+
+	key := resolveKey{
+		name:         name,
+		fileFallback: e.fileFallback,
+		trimSpace:    e.trimSpace,
+		unquote:      e.unquote,
+		emptyAsUnset: e.emptyAsUnset,
+	}
+	e.cache.mu.RLock()
+	r, found := e.cache.resolved[key]
+	e.cache.mu.RUnlock()
+	if found {
+		return r.val, r.ok, r.src, nil
+	}
+
+	val, ok, src, err := e.lookupName(name)
+	if err != nil {
+		return "", false, nil, err
+	}
+
+	e.cache.mu.Lock()
+	e.cache.resolved[key] = resolvedValue{val: val, ok: ok, src: src}
+	e.cache.mu.Unlock()
+
+	return val, ok, src, nil
+}
+
+// parsed returns the remembered result of parsing raw as type typ
+func (e *Env) parsed(typ, raw string, literals bool) (parsedValue, bool) {
+	if e.cache == nil {
+		return parsedValue{}, false
+	}
+
+	e.cache.mu.RLock()
+	p, found := e.cache.parsed[parseKey{typ: typ, raw: raw, literals: literals}]
+	e.cache.mu.RUnlock()
+
+	return p, found
+}
+
+// remember remembers the result of parsing raw as type typ if the Env has a cache
+func (e *Env) remember(typ, raw string, literals bool, p parsedValue) {
+	if e.cache == nil {
+		return
+	}
+
+	e.cache.mu.Lock()
+	e.cache.parsed[parseKey{typ: typ, raw: raw, literals: literals}] = p
+	e.cache.mu.Unlock()
+}
+
+func (e *Env) parseBool(raw string) (bool, error) {
+	if p, ok := e.parsed("bool", raw, false); ok {
+		return p.b, p.err
+	}
+
+	b, err := strconv.ParseBool(raw)
+	e.remember("bool", raw, false, parsedValue{b: b, err: err})
+
+	return b, err
+}
+
+func (e *Env) parseDuration(raw string) (time.Duration, error) {
+	if p, ok := e.parsed("time.Duration", raw, false); ok {
+		return time.Duration(p.i), p.err
+	}
+
+	d, err := time.ParseDuration(raw)
+	e.remember("time.Duration", raw, false, parsedValue{i: int64(d), err: err})
+
+	return d, err
+}
+
+func (e *Env) parseFloat(raw string) (float64, error) {
+	if p, ok := e.parsed("float64", raw, false); ok {
+		return p.f, p.err
+	}
+
+	f, err := strconv.ParseFloat(raw, 64)
+	e.remember("float64", raw, false, parsedValue{f: f, err: err})
+
+	return f, err
+}
+
+func (e *Env) parseInt(typ, raw string, bitSize int, o *options) (int64, error) {
+	if p, ok := e.parsed(typ, raw, o.literals); ok {
+		return p.i, p.err
+	}
+
+	i, err := o.parseInt(raw, bitSize)
+	e.remember(typ, raw, o.literals, parsedValue{i: i, err: err})
+
+	return i, err
+}
+
+func (e *Env) parseUint(typ, raw string, bitSize int, o *options) (uint64, error) {
+	if p, ok := e.parsed(typ, raw, o.literals); ok {
+		return p.u, p.err
+	}
+
+	u, err := o.parseUint(raw, bitSize)
+	e.remember(typ, raw, o.literals, parsedValue{u: u, err: err})
+
+	return u, err
+}
@@ -0,0 +1,172 @@
+package defenv
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingSource struct {
+	MapSource
+	lookups int
+}
+
+func (s *countingSource) Lookup(name string) (string, bool) {
+	s.lookups++
+	return s.MapSource.Lookup(name)
+}
+
+func TestCached(t *testing.T) {
+	var (
+		now = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+		src = &countingSource{MapSource: MapSource{"PORT": "8080"}}
+	)
+
+	cached := Cached(src, time.Minute)
+	cached.now = func() time.Time { return now }
+
+	check := func(name, expRes string, expOk bool, expLookups int) {
+		res, ok := cached.Lookup(name)
+		if ok != expOk || res != expRes {
+			t.Errorf("expected %s=%q (%t), got: %q (%t)", name, expRes, expOk, res, ok)
+		}
+		if src.lookups != expLookups {
+			t.Errorf("expected %d lookups of source, got: %d", expLookups, src.lookups)
+		}
+	}
+
+	check("PORT", "8080", true, 1)
+	check("PORT", "8080", true, 1)
+	check("ABSENT", "", false, 2)
+	check("ABSENT", "", false, 2)
+
+	src.MapSource["PORT"] = "9090"
+	now = now.Add(59 * time.Second)
+	check("PORT", "8080", true, 2)
+
+	now = now.Add(time.Second)
+	check("PORT", "9090", true, 3)
+
+	src.MapSource["PORT"] = "7070"
+	cached.Invalidate()
+	check("PORT", "7070", true, 4)
+	check("PORT", "7070", true, 4)
+}
+
+func TestCachedWithoutTTL(t *testing.T) {
+	var (
+		now = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+		src = &countingSource{MapSource: MapSource{"PORT": "8080"}}
+	)
+
+	cached := Cached(src, 0)
+	cached.now = func() time.Time { return now }
+
+	cached.Lookup("PORT")
+	now = now.Add(24 * time.Hour)
+	cached.Lookup("PORT")
+	if src.lookups != 1 {
+		t.Errorf("expected 1 lookup of source, got: %d", src.lookups)
+	}
+
+	cached.Invalidate()
+	cached.Lookup("PORT")
+	if src.lookups != 2 {
+		t.Errorf("expected 2 lookups of source, got: %d", src.lookups)
+	}
+}
+
+func TestCachedLookupContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	src := &ctxSource{MapSource: MapSource{"PORT": "8080"}}
+	cached := Cached(src, time.Minute)
+
+	if _, _, err := cached.LookupContext(canceled, "PORT"); err != context.Canceled {
+		t.Errorf("expected error: %v, got: %v", context.Canceled, err)
+	}
+	if res, ok, err := cached.LookupContext(context.Background(), "PORT"); err != nil || !ok || res != "8080" {
+		t.Errorf("expected value: %s, got: %s (%t, %v)", "8080", res, ok, err)
+	}
+	if res, ok, err := cached.LookupContext(canceled, "PORT"); err != nil || !ok || res != "8080" {
+		t.Errorf("expected cached value: %s, got: %s (%t, %v)", "8080", res, ok, err)
+	}
+	if src.lookups != 2 {
+		t.Errorf("expected 2 lookups of source, got: %d", src.lookups)
+	}
+}
+
+func TestCachedConcurrentLookups(t *testing.T) {
+	var lookups int32
+	cached := Cached(LookupFunc(func(name string) (string, bool) {
+		atomic.AddInt32(&lookups, 1)
+		time.Sleep(10 * time.Millisecond)
+		return "8080", true
+	}), time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res, ok := cached.Lookup("PORT"); !ok || res != "8080" {
+				t.Errorf("expected value: %s, got: %s (%t)", "8080", res, ok)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&lookups); n != 1 {
+		t.Errorf("expected 1 lookup of source, got: %d", n)
+	}
+}
+
+func TestEnvWithCache(t *testing.T) {
+	var lookups int
+	src := MapSource{"PORT": "80", "HOST": " localhost "}
+	env := NewEnv(LookupFunc(func(name string) (string, bool) {
+		lookups++
+		return src.Lookup(name)
+	})).WithCache()
+
+	for i := 0; i < 3; i++ {
+		if port := env.Int("PORT", 0); port != 80 {
+			t.Fatalf("expected port: %d, got: %d", 80, port)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("expected lookups: %d, got: %d", 1, lookups)
+	}
+
+	if host := env.String("HOST", ""); host != " localhost " {
+		t.Errorf("expected host: %q, got: %q", " localhost ", host)
+	}
+	if host := env.WithTrimSpace().String("HOST", ""); host != "localhost" {
+		t.Errorf("expected host: %q, got: %q", "localhost", host)
+	}
+
+	src["PORT"] = "8080"
+	if port := env.Int("PORT", 0); port != 80 {
+		t.Errorf("expected cached port: %d, got: %d", 80, port)
+	}
+
+	env.ResetCache()
+	if port := env.Int("PORT", 0); port != 8080 {
+		t.Errorf("expected port: %d, got: %d", 8080, port)
+	}
+
+	src["PORT"] = "abc"
+	env.ResetCache()
+	for i := 0; i < 2; i++ {
+		_, err := env.IntStrict("PORT", 0)
+		expErr := `defenv: parse PORT="abc" as int: invalid syntax`
+		if err == nil || err.Error() != expErr {
+			t.Errorf("expected error: %s, got: %v", expErr, err)
+		}
+	}
+
+	NewEnv(src).ResetCache()
+}
@@ -0,0 +1,98 @@
+package defenv
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Collector records failures of ordinary getters, so all misconfigurations
+// can be reported at once:
+//
+// c := defenv.NewCollector()
+// port := c.Int("PORT", 8080)
+// timeout := c.Duration("TIMEOUT", time.Second)
+// err := c.Err() // reports both PORT and TIMEOUT if they are invalid
+//
+// Ordinary getters of a Collector return the default value if a variable
+// can not be parsed and record the error. Strict getters return errors
+// without recording them. Env copies returned by With... methods of
+// a Collector record failures to the same Collector
+type Collector struct {
+	*Env
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewCollector returns Collector reading the process environment
+func NewCollector() *Collector {
+	return std.Collector()
+}
+
+// Collector returns Collector reading variables the same way as the Env
+func (e *Env) Collector() *Collector {
+	c := &Collector{}
+	c.Env = e.WithErrorHandler(c.add)
+	return c
+}
+
+func (c *Collector) add(err error) {
+	c.mu.Lock()
+	c.errs = append(c.errs, err)
+	c.mu.Unlock()
+}
+
+// Err returns an error joining all recorded errors,
+// or nil if nothing was recorded
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.errs) == 0 {
+		return nil
+	}
+
+	return multiError(append([]error{}, c.errs...))
+}
+
+// multiError joins several errors, one per line
+type multiError []error
+
+func (m multiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the joined errors
+func (m multiError) Unwrap() []error {
+	return m
+}
+
+// Is reports whether any of the joined errors matches target.
+// errors.Is uses Unwrap() []error only since Go 1.20
+func (m multiError) Is(target error) bool {
+	for _, err := range m {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the joined errors matching target.
+// errors.As uses Unwrap() []error only since Go 1.20
+func (m multiError) As(target interface{}) bool {
+	for _, err := range m {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewEnv(MapSource{
+		"PORT":       "abc",
+		"TIMEOUT":    "5s",
+		"WORKERS":    "0",
+		"APP_DEBUG":  "maybe",
+		"SAMPLE":     "2",
+		"WORKERS_OK": "4",
+	}).Collector()
+
+	if res := c.Int("PORT", 8080); res != 8080 {
+		t.Errorf("expected value: %d, got: %d", 8080, res)
+	}
+	if res := c.Duration("TIMEOUT", time.Second); res != 5*time.Second {
+		t.Errorf("expected value: %s, got: %s", 5*time.Second, res)
+	}
+	if res := c.PositiveInt("WORKERS", 8); res != 8 {
+		t.Errorf("expected value: %d, got: %d", 8, res)
+	}
+	if res := c.WithPrefix("APP_").Bool("DEBUG", false); res {
+		t.Errorf("expected value: %t, got: %t", false, res)
+	}
+	if _, err := c.ProbabilityStrict("SAMPLE", 0.1); err == nil {
+		t.Error("expected error from strict getter")
+	}
+	if res := c.Int("WORKERS_OK", 8); res != 4 {
+		t.Errorf("expected value: %d, got: %d", 4, res)
+	}
+
+	expErr := `defenv: parse PORT="abc" as int: invalid syntax
+defenv: WORKERS="0" is not positive
+defenv: parse APP_DEBUG="maybe" as bool: invalid syntax`
+	if err := c.Err(); fmt.Sprint(err) != expErr {
+		t.Errorf("expected error: %s, got: %v", expErr, err)
+	}
+
+	err := c.Err()
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected error wrapping: %v, got: %v", strconv.ErrSyntax, err)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Var != "PORT" {
+		t.Errorf("expected parse error of %s, got: %v", "PORT", perr)
+	}
+	if errors.Is(err, ErrNotSet) {
+		t.Errorf("expected error not wrapping: %v", ErrNotSet)
+	}
+}
+
+func TestCollectorWithoutErrors(t *testing.T) {
+	c := NewEnv(MapSource{"PORT": "80"}).Collector()
+
+	if res := c.Int("PORT", 8080); res != 80 {
+		t.Errorf("expected value: %d, got: %d", 80, res)
+	}
+	if err := c.Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
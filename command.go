@@ -0,0 +1,187 @@
+package defenv
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// Command extracts a command line from environment variable named name
+// and returns defaultValue if it is absent or can not be parsed.
+// The value is split into arguments using shell quoting rules:
+// single and double quotes group words and backslash escapes the next character.
+// Arguments from name_PREPEND and name_APPEND variables are added
+// before and after the command line respectively
+func Command(name string, defaultValue []string, opts ...Option) []string {
+	return std.Command(name, defaultValue, opts...)
+}
+
+// Command extracts a command line from variable named name
+// and returns defaultValue if it is absent or can not be parsed.
+// The value is split into arguments using shell quoting rules:
+// single and double quotes group words and backslash escapes the next character.
+// Arguments from name_PREPEND and name_APPEND variables are added
+// before and after the command line respectively
+func (e *Env) Command(name string, defaultValue []string, opts ...Option) []string {
+	args, err := e.lookupCommand(name, defaultValue, newOptions(opts))
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return args
+}
+
+// CommandStrict extracts a command line from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed, the method returns an error
+func CommandStrict(name string, defaultValue []string, opts ...Option) ([]string, error) {
+	return std.CommandStrict(name, defaultValue, opts...)
+}
+
+// CommandStrict extracts a command line from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) CommandStrict(name string, defaultValue []string, opts ...Option) ([]string, error) {
+	return e.lookupCommand(name, defaultValue, newOptions(opts))
+}
+
+// Args is an alias of Command for variables holding extra arguments
+// of a wrapped binary, e.g. EXTRA_FLAGS. Arguments from name_PREPEND
+// and name_APPEND variables are added as well
+func Args(name string, defaultValue []string, opts ...Option) []string {
+	return std.Command(name, defaultValue, opts...)
+}
+
+// Args is an alias of Command for variables holding extra arguments
+// of a wrapped binary, e.g. EXTRA_FLAGS. Arguments from name_PREPEND
+// and name_APPEND variables are added as well
+func (e *Env) Args(name string, defaultValue []string, opts ...Option) []string {
+	return e.Command(name, defaultValue, opts...)
+}
+
+// ArgsStrict is an alias of CommandStrict
+func ArgsStrict(name string, defaultValue []string, opts ...Option) ([]string, error) {
+	return std.CommandStrict(name, defaultValue, opts...)
+}
+
+// ArgsStrict is an alias of CommandStrict
+func (e *Env) ArgsStrict(name string, defaultValue []string, opts ...Option) ([]string, error) {
+	return e.CommandStrict(name, defaultValue, opts...)
+}
+
+func (e *Env) lookupCommand(name string, defaultValue []string, o options) ([]string, error) {
+	if !e.registered([]string{name}) {
+		e.register([]string{name}, func(env *Env) error {
+			_, err := env.lookupCommand(name, defaultValue, o)
+			return err
+		})
+	}
+
+	args, ok, err := e.words(name, otherDef(defaultValue), o)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		args = defaultValue
+	}
+
+	prefix, ok, err := e.words(name+prependSuffix, defValue{}, o.companion())
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		args = append(prefix, args...)
+	}
+
+	suffix, ok, err := e.words(name+appendSuffix, defValue{}, o.companion())
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		args = append(append([]string{}, args...), suffix...)
+	}
+
+	return args, nil
+}
+
+// words returns value of variable named name split into words
+// and reports whether the variable is present
+func (e *Env) words(name string, def defValue, o options) ([]string, bool, error) {
+	name, strVal, ok, err := e.value([]string{name}, def, o)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	args, err := splitWords(strVal)
+	if err != nil {
+		return nil, true, &ParseError{Var: name, Raw: strVal, Type: "[]string", Err: err}
+	}
+
+	return args, true, nil
+}
+
+// splitWords splits s into words the way a POSIX shell does,
+// without performing any expansions
+func splitWords(s string) ([]string, error) {
+	var (
+		words  = []string{}
+		word   bytes.Buffer
+		inWord bool
+	)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			i++
+			if i == len(s) {
+				return nil, errors.New("unterminated escape sequence")
+			}
+			if s[i] == '\n' {
+				// line continuation, does not start a word
+				continue
+			}
+			word.WriteByte(s[i])
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\\\"$`\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, errors.New("unterminated double quote")
+			}
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
@@ -0,0 +1,194 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	def := []string{"true"}
+
+	for _, tc := range []struct {
+		name         string
+		setEnv       bool
+		envValue     string
+		defaultValue []string
+		expRes       []string
+	}{
+		{
+			name:         `argv then environment value is "nice -n 10 ffmpeg"`,
+			setEnv:       true,
+			envValue:     "nice -n 10 ffmpeg",
+			defaultValue: def,
+			expRes:       []string{"nice", "-n", "10", "ffmpeg"},
+		},
+		{
+			name:         `use default value then environment value has unterminated quote`,
+			setEnv:       true,
+			envValue:     `echo "hello`,
+			defaultValue: def,
+			expRes:       def,
+		},
+		{
+			name:         `use default value then environment value is not set`,
+			setEnv:       false,
+			defaultValue: def,
+			expRes:       def,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if err := os.Unsetenv("VALUE"); err != nil {
+					t.Errorf("coudn't unset VALUE: %s", err)
+				}
+			}()
+
+			if tc.setEnv {
+				if err := os.Setenv("VALUE", tc.envValue); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res := Command("VALUE", tc.defaultValue)
+			if !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %q, got: %q", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestCommandStrict(t *testing.T) {
+	def := []string{"true"}
+
+	for _, tc := range []struct {
+		name         string
+		setEnv       bool
+		envValue     string
+		defaultValue []string
+		expRes       []string
+		expErr       error
+	}{
+		{
+			name:         `argv from environment with quotes and escapes`,
+			setEnv:       true,
+			envValue:     `ffmpeg -i 'my file.mp4' -metadata "title=\"A \$B\"" out\ put.mp4`,
+			defaultValue: def,
+			expRes:       []string{"ffmpeg", "-i", "my file.mp4", "-metadata", `title="A $B"`, "out put.mp4"},
+		},
+		{
+			name:         `empty arguments from environment as "a '' \"\""`,
+			setEnv:       true,
+			envValue:     `a '' ""`,
+			defaultValue: def,
+			expRes:       []string{"a", "", ""},
+		},
+		{
+			name:         `adjacent quoted parts are joined`,
+			setEnv:       true,
+			envValue:     `--name='a b'"c d"e`,
+			defaultValue: def,
+			expRes:       []string{"--name=a bc de"},
+		},
+		{
+			name:         `line continuation between words does not add an argument`,
+			setEnv:       true,
+			envValue:     "foo \\\n bar",
+			defaultValue: def,
+			expRes:       []string{"foo", "bar"},
+		},
+		{
+			name:         `line continuation inside a word joins it`,
+			setEnv:       true,
+			envValue:     "foo\\\nbar",
+			defaultValue: def,
+			expRes:       []string{"foobar"},
+		},
+		{
+			name:         `empty argv then environment value is "  "`,
+			setEnv:       true,
+			envValue:     "  ",
+			defaultValue: def,
+			expRes:       []string{},
+		},
+		{
+			name:         `fail then environment value has unterminated single quote`,
+			setEnv:       true,
+			envValue:     `echo 'hello`,
+			defaultValue: def,
+			expErr:       errors.New(`defenv: parse VALUE="echo 'hello" as []string: unterminated single quote`),
+		},
+		{
+			name:         `fail then environment value has unterminated double quote`,
+			setEnv:       true,
+			envValue:     `echo "hello`,
+			defaultValue: def,
+			expErr:       errors.New(`defenv: parse VALUE="echo \"hello" as []string: unterminated double quote`),
+		},
+		{
+			name:         `fail then environment value ends with backslash`,
+			setEnv:       true,
+			envValue:     `echo \`,
+			defaultValue: def,
+			expErr:       errors.New(`defenv: parse VALUE="echo \\" as []string: unterminated escape sequence`),
+		},
+		{
+			name:         `use default value then environment value is not set`,
+			setEnv:       false,
+			defaultValue: def,
+			expRes:       def,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if err := os.Unsetenv("VALUE"); err != nil {
+					t.Errorf("coudn't unset VALUE: %s", err)
+				}
+			}()
+
+			if tc.setEnv {
+				if err := os.Setenv("VALUE", tc.envValue); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res, err := CommandStrict("VALUE", tc.defaultValue)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %q, got: %q", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestArgs(t *testing.T) {
+	env := NewEnv(MapSource{
+		"EXTRA_FLAGS":        `--label 'my app' -v`,
+		"EXTRA_FLAGS_APPEND": "--debug",
+		"BAD_FLAGS":          `--label "my app`,
+	})
+
+	res := env.Args("EXTRA_FLAGS", nil)
+	exp := []string{"--label", "my app", "-v", "--debug"}
+	if !reflect.DeepEqual(res, exp) {
+		t.Errorf("expected value: %q, got: %q", exp, res)
+	}
+
+	if res := env.Args("ABSENT_FLAGS", nil); res != nil {
+		t.Errorf("expected value: nil, got: %q", res)
+	}
+
+	if res := env.Args("BAD_FLAGS", []string{"-q"}); !reflect.DeepEqual(res, []string{"-q"}) {
+		t.Errorf("expected value: %q, got: %q", []string{"-q"}, res)
+	}
+
+	_, err := env.ArgsStrict("BAD_FLAGS", nil)
+	expErr := errors.New(`defenv: parse BAD_FLAGS="--label \"my app" as []string: unterminated double quote`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+}
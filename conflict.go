@@ -0,0 +1,75 @@
+package defenv
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// OnConflict sets hook called when an environment variable is read
+// through the package level functions with a default value different
+// from the one used by an earlier read, e.g. when two packages
+// both read TIMEOUT with different defaults. Only reads made after
+// the hook is set are checked
+func OnConflict(hook func(name string, first, second interface{})) {
+	std.OnConflict(hook)
+}
+
+// OnConflict sets hook called when a variable is read through the Env
+// or its copies with a default value different from the one used by
+// an earlier read. The hook receives name of the variable, the first
+// default value and the conflicting one, and is called once for every
+// distinct conflicting value. Reads are checked whether the variable
+// is present or not. Only reads made after the hook is set are checked,
+// without a hook reads do not record their default values
+func (e *Env) OnConflict(hook func(name string, first, second interface{})) {
+	e.conflicts.mu.Lock()
+	e.conflicts.hook = hook
+	var enabled int32
+	if hook != nil {
+		enabled = 1
+	}
+	atomic.StoreInt32(&e.conflicts.enabled, enabled)
+	e.conflicts.mu.Unlock()
+}
+
+// conflicts records the first default value of every variable.
+// It is shared by all copies of an Env
+type conflicts struct {
+	enabled  int32 // a hook is set, accessed atomically
+	mu       sync.Mutex
+	hook     func(name string, first, second interface{})
+	defaults map[string]defValue
+	reported map[string]bool
+}
+
+func newConflicts() *conflicts {
+	return &conflicts{defaults: make(map[string]defValue), reported: make(map[string]bool)}
+}
+
+// check records def as the default value of variable named name
+// and calls the hook if it differs from the recorded one
+func (c *conflicts) check(name string, def defValue) {
+	if c == nil || !def.isSet() || atomic.LoadInt32(&c.enabled) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	first, ok := c.defaults[name]
+	if !ok {
+		c.defaults[name] = def
+	}
+
+	var report bool
+	if ok && c.hook != nil && !first.equal(def) {
+		key := name + "=" + fmt.Sprintf("%#v", def.value())
+		report = !c.reported[key]
+		c.reported[key] = true
+	}
+	hook := c.hook
+	c.mu.Unlock()
+
+	if report {
+		hook(name, first.value(), def.value())
+	}
+}
@@ -0,0 +1,36 @@
+package defenv
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEnvOnConflict(t *testing.T) {
+	var conflicts []string
+	env := NewEnv(MapSource{"TIMEOUT": "5s"})
+	env.OnConflict(func(name string, first, second interface{}) {
+		conflicts = append(conflicts, fmt.Sprintf("%s: %v != %v", name, first, second))
+	})
+
+	env.Duration("TIMEOUT", time.Second)
+	env.Duration("TIMEOUT", time.Second)
+	env.Duration("TIMEOUT", 2*time.Second)
+	env.Duration("TIMEOUT", 2*time.Second)
+	env.WithPrefix("APP_").Int("WORKERS", 4)
+	env.Int("APP_WORKERS", 8)
+	env.Int("ABSENT", 1)
+	env.Int("ABSENT", 2)
+	env.Command("CMD", []string{"run"})
+	env.Command("CMD", []string{"run"})
+
+	expConflicts := []string{
+		"TIMEOUT: 1s != 2s",
+		"APP_WORKERS: 4 != 8",
+		"ABSENT: 1 != 2",
+	}
+	if !reflect.DeepEqual(conflicts, expConflicts) {
+		t.Errorf("expected conflicts: %v, got: %v", expConflicts, conflicts)
+	}
+}
@@ -0,0 +1,150 @@
+package defenv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DateList extracts a list of dates from environment variable named name
+// and returns defaultValue if it is absent or can not be parsed.
+// The value is a comma-separated list of dates in YYYY-MM-DD format,
+// the result is sorted in ascending order. Dates from name_PREPEND and
+// name_APPEND variables are merged into the list
+func DateList(name string, defaultValue []time.Time, opts ...Option) []time.Time {
+	return std.DateList(name, defaultValue, opts...)
+}
+
+// DateList extracts a list of dates from variable named name
+// and returns defaultValue if it is absent or can not be parsed.
+// The value is a comma-separated list of dates in YYYY-MM-DD format,
+// the result is sorted in ascending order. Dates from name_PREPEND and
+// name_APPEND variables are merged into the list
+func (e *Env) DateList(name string, defaultValue []time.Time, opts ...Option) []time.Time {
+	dates, err := e.lookupDateList(name, defaultValue, newOptions(opts))
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return dates
+}
+
+// DateListStrict extracts a list of dates from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed or contains the same date twice, the method returns an error
+func DateListStrict(name string, defaultValue []time.Time, opts ...Option) ([]time.Time, error) {
+	return std.DateListStrict(name, defaultValue, opts...)
+}
+
+// DateListStrict extracts a list of dates from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed or contains the same date twice, the method returns an error
+func (e *Env) DateListStrict(name string, defaultValue []time.Time, opts ...Option) ([]time.Time, error) {
+	return e.lookupDateList(name, defaultValue, newOptions(opts))
+}
+
+func (e *Env) lookupDateList(name string, defaultValue []time.Time, o options) ([]time.Time, error) {
+	if !e.registered([]string{name}) {
+		e.register([]string{name}, func(env *Env) error {
+			_, err := env.lookupDateList(name, defaultValue, o)
+			return err
+		})
+	}
+
+	var (
+		dates   = []time.Time{}
+		changed bool
+	)
+
+	varName, raw, base, ok, err := e.dates(name, otherDef(defaultValue), o)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if dates, err = mergeDates(dates, base, varName, raw); err != nil {
+			return nil, err
+		}
+		changed = true
+	} else {
+		dates = append(dates, defaultValue...)
+	}
+
+	for _, companion := range []string{name + prependSuffix, name + appendSuffix} {
+		varName, raw, extra, ok, err := e.dates(companion, defValue{}, o.companion())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if dates, err = mergeDates(dates, extra, varName, raw); err != nil {
+				return nil, err
+			}
+			changed = true
+		}
+	}
+
+	if !changed {
+		return defaultValue, nil
+	}
+
+	sort.Sort(byTime(dates))
+
+	return dates, nil
+}
+
+// dates returns name and value of variable named name and dates from it,
+// and reports whether the variable is present
+func (e *Env) dates(name string, def defValue, o options) (string, string, []time.Time, bool, error) {
+	name, strVal, ok, err := e.value([]string{name}, def, o)
+	if err != nil || !ok {
+		return name, strVal, nil, false, err
+	}
+
+	dates, err := parseDateList(strVal)
+	if err != nil {
+		return name, strVal, nil, true, &ParseError{Var: name, Raw: strVal, Type: "[]time.Time", Err: err}
+	}
+
+	return name, strVal, dates, true, nil
+}
+
+func parseDateList(s string) ([]time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return []time.Time{}, nil
+	}
+
+	parts := strings.Split(s, ",")
+	dates := make([]time.Time, 0, len(parts))
+	for _, part := range parts {
+		d, err := time.Parse("2006-01-02", strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+
+		dates = append(dates, d)
+	}
+
+	return dates, nil
+}
+
+// mergeDates appends dates read from variable named name with value raw
+// to merged and returns an error if a date is already there
+func mergeDates(merged, dates []time.Time, name, raw string) ([]time.Time, error) {
+	for _, d := range dates {
+		for _, m := range merged {
+			if d.Equal(m) {
+				return nil, &varError{name: name, code: CodeDuplicate, msg: fmt.Sprintf("defenv: %s=%s contains duplicate date %s", name, quoteValue(name, raw), d.Format("2006-01-02"))}
+			}
+		}
+		merged = append(merged, d)
+	}
+
+	return merged, nil
+}
+
+type byTime []time.Time
+
+func (t byTime) Len() int           { return len(t) }
+func (t byTime) Less(i, j int) bool { return t[i].Before(t[j]) }
+func (t byTime) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
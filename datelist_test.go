@@ -0,0 +1,143 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDateList(t *testing.T) {
+	def := []time.Time{date(2018, 1, 1)}
+
+	for _, tc := range []struct {
+		name         string
+		setEnv       bool
+		envValue     string
+		defaultValue []time.Time
+		expRes       []time.Time
+	}{
+		{
+			name:         `sorted dates then environment value is "2018-12-31, 2018-01-07"`,
+			setEnv:       true,
+			envValue:     "2018-12-31, 2018-01-07",
+			defaultValue: def,
+			expRes:       []time.Time{date(2018, 1, 7), date(2018, 12, 31)},
+		},
+		{
+			name:         `use default value then environment value has duplicates`,
+			setEnv:       true,
+			envValue:     "2018-01-07,2018-01-07",
+			defaultValue: def,
+			expRes:       def,
+		},
+		{
+			name:         `use default value then environment value is "bad"`,
+			setEnv:       true,
+			envValue:     "bad",
+			defaultValue: def,
+			expRes:       def,
+		},
+		{
+			name:         `use default value then environment value is not set`,
+			setEnv:       false,
+			defaultValue: def,
+			expRes:       def,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if err := os.Unsetenv("VALUE"); err != nil {
+					t.Errorf("coudn't unset VALUE: %s", err)
+				}
+			}()
+
+			if tc.setEnv {
+				if err := os.Setenv("VALUE", tc.envValue); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res := DateList("VALUE", tc.defaultValue)
+			if !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %v, got: %v", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestDateListStrict(t *testing.T) {
+	def := []time.Time{date(2018, 1, 1)}
+
+	for _, tc := range []struct {
+		name         string
+		setEnv       bool
+		envValue     string
+		defaultValue []time.Time
+		expRes       []time.Time
+		expErr       error
+	}{
+		{
+			name:         `sorted dates from environment as "2018-03-08,2018-02-23,2018-01-01"`,
+			setEnv:       true,
+			envValue:     "2018-03-08,2018-02-23,2018-01-01",
+			defaultValue: def,
+			expRes:       []time.Time{date(2018, 1, 1), date(2018, 2, 23), date(2018, 3, 8)},
+		},
+		{
+			name:         `empty list then environment value is ""`,
+			setEnv:       true,
+			envValue:     "",
+			defaultValue: def,
+			expRes:       []time.Time{},
+		},
+		{
+			name:         `fail then environment value has duplicates`,
+			setEnv:       true,
+			envValue:     "2018-05-01,2018-01-07,2018-05-01",
+			defaultValue: def,
+			expErr:       errors.New(`defenv: VALUE="2018-05-01,2018-01-07,2018-05-01" contains duplicate date 2018-05-01`),
+		},
+		{
+			name:         `fail then environment value is "2018-02-30"`,
+			setEnv:       true,
+			envValue:     "2018-02-30",
+			defaultValue: def,
+			expErr:       errors.New(`defenv: parse VALUE="2018-02-30" as []time.Time: parsing time "2018-02-30": day out of range`),
+		},
+		{
+			name:         `use default value then environment value is not set`,
+			setEnv:       false,
+			defaultValue: def,
+			expRes:       def,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if err := os.Unsetenv("VALUE"); err != nil {
+					t.Errorf("coudn't unset VALUE: %s", err)
+				}
+			}()
+
+			if tc.setEnv {
+				if err := os.Setenv("VALUE", tc.envValue); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res, err := DateListStrict("VALUE", tc.defaultValue)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %v, got: %v", tc.expRes, res)
+			}
+		})
+	}
+}
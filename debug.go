@@ -0,0 +1,64 @@
+package defenv
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// debugVar is name of the environment variable enabling the debug trace
+const debugVar = "DEFENV_DEBUG"
+
+var debug = struct {
+	mu sync.Mutex
+	w  io.Writer
+	on int32 // 1 if w is not nil, read atomically to keep the disabled trace cheap
+}{}
+
+func init() {
+	SetDebugOutput(debugOutput(os.Getenv(debugVar)))
+}
+
+// debugOutput returns the writer of the debug trace for value of DEFENV_DEBUG
+func debugOutput(val string) io.Writer {
+	if on, _ := strconv.ParseBool(val); on {
+		return os.Stderr
+	}
+
+	return nil
+}
+
+// SetDebugOutput sets the writer of the debug trace, nil disables it.
+// The trace contains every lookup of a variable by any Env, the source
+// containing it and values that can not be parsed by ordinary getters.
+// Values of sensitive variables are redacted. If DEFENV_DEBUG environment
+// variable is set to 1 or true, the trace is written to stderr
+func SetDebugOutput(w io.Writer) {
+	debug.mu.Lock()
+	debug.w = w
+	if w != nil {
+		atomic.StoreInt32(&debug.on, 1)
+	} else {
+		atomic.StoreInt32(&debug.on, 0)
+	}
+	debug.mu.Unlock()
+}
+
+// debugging reports whether the debug trace is enabled,
+// callers check it to avoid formatting arguments of debugf
+func debugging() bool {
+	return atomic.LoadInt32(&debug.on) == 1
+}
+
+// debugf writes a line to the debug trace if it is enabled
+func debugf(format string, args ...interface{}) {
+	debug.mu.Lock()
+	defer debug.mu.Unlock()
+
+	if debug.w != nil {
+		fmt.Fprintf(debug.w, format+"\n", args...)
+	}
+}
@@ -0,0 +1,50 @@
+package defenv
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSetDebugOutput(t *testing.T) {
+	defer resetSensitive()
+	defer SetDebugOutput(nil)
+
+	MarkSensitive("DB_PASSWORD")
+
+	var buf bytes.Buffer
+	SetDebugOutput(&buf)
+
+	env := NewEnv(MapSource{"PORT": "abc", "DB_PASSWORD": "secret", "SERVICE_HOST": ""})
+	env.Int("PORT", 8080)
+	env.String("DB_PASSWORD", "")
+	env.Duration("TIMEOUT", time.Second)
+	env.String("SERVICE_HOST", "localhost", EmptyAsUnset())
+
+	expTrace := `defenv: lookup PORT: "abc" from defenv.MapSource
+defenv: parse PORT="abc" as int: invalid syntax, default used
+defenv: lookup DB_PASSWORD: [REDACTED] from defenv.MapSource
+defenv: lookup TIMEOUT: absent
+defenv: TIMEOUT: default 1s used
+defenv: lookup SERVICE_HOST: empty, skipped
+defenv: SERVICE_HOST: default "localhost" used
+`
+	if buf.String() != expTrace {
+		t.Errorf("expected trace:\n%s\ngot:\n%s", expTrace, buf.String())
+	}
+
+	SetDebugOutput(nil)
+	buf.Reset()
+	env.Int("PORT", 8080)
+	if buf.Len() != 0 {
+		t.Errorf("expected no trace, got:\n%s", buf.String())
+	}
+}
+
+func TestDebugOutput(t *testing.T) {
+	for val, expEnabled := range map[string]bool{"": false, "0": false, "1": true, "true": true, "bad": false} {
+		if enabled := debugOutput(val) != nil; enabled != expEnabled {
+			t.Errorf("expected trace enabled for %q: %t, got: %t", val, expEnabled, enabled)
+		}
+	}
+}
@@ -10,217 +10,124 @@
 //
 // value, err := defenv.IntStrict("WORKER_NUMBER", 8)
 //
+// Package level functions read the process environment. The same methods
+// are available on Env, which reads variables from arbitrary sources.
+//
+// env := defenv.NewEnv(source, defenv.OS)
+// value := env.Int("WORKER_NUMBER", 8)
+//
+// Package level functions and Env methods are safe for concurrent use.
+//
 package defenv
 
-import (
-	"os"
-	"strconv"
-	"time"
-)
+import "time"
 
 // Bool extracts bool value from environment variable named name
 // and returns defaultValue if it is absent or can not be parsed
-func Bool(name string, defaultValue bool) bool {
-	if strVal, ok := os.LookupEnv(name); ok {
-		if res, err := strconv.ParseBool(strVal); err == nil {
-			return res
-		}
-	}
-
-	return defaultValue
+func Bool(name string, defaultValue bool, opts ...Option) bool {
+	return std.Bool(name, defaultValue, opts...)
 }
 
 // BoolStrict extracts bool value from environment variable named name
 // and returns defaultValue if it is absent. If the environment variable
 // can not be parsed, the method returns an error
-func BoolStrict(name string, defaultValue bool) (bool, error) {
-	if strVal, ok := os.LookupEnv(name); ok {
-		res, err := strconv.ParseBool(strVal)
-		if err != nil {
-			return false, err
-		}
-
-		return res, nil
-	}
-
-	return defaultValue, nil
+func BoolStrict(name string, defaultValue bool, opts ...Option) (bool, error) {
+	return std.BoolStrict(name, defaultValue, opts...)
 }
 
 // Duration extracts time.Duration value from environment variable named name
 // and returns defaultValue if it is absent or can not be parsed
-func Duration(name string, defaultValue time.Duration) time.Duration {
-	if strVal, ok := os.LookupEnv(name); ok {
-		if d, err := time.ParseDuration(strVal); err == nil {
-			return d
-		}
-	}
-
-	return defaultValue
+func Duration(name string, defaultValue time.Duration, opts ...Option) time.Duration {
+	return std.Duration(name, defaultValue, opts...)
 }
 
 // DurationStrict extracts time.Duration value from environment variable named name
 // and returns defaultValue if it is absent. If the environment variable
 // can not be parsed, the method returns an error
-func DurationStrict(name string, defaultValue time.Duration) (time.Duration, error) {
-	if strVal, ok := os.LookupEnv(name); ok {
-		d, err := time.ParseDuration(strVal)
-		if err != nil {
-			return 0, err
-		}
-
-		return d, nil
-	}
-
-	return defaultValue, nil
+func DurationStrict(name string, defaultValue time.Duration, opts ...Option) (time.Duration, error) {
+	return std.DurationStrict(name, defaultValue, opts...)
 }
 
 // Float64 extracts float64 value from environment variable named name
 // and returns defaultValue if it is absent or can not be parsed
-func Float64(name string, defaultValue float64) float64 {
-	if strVal, ok := os.LookupEnv(name); ok {
-		if f, err := strconv.ParseFloat(strVal, 64); err == nil {
-			return f
-		}
-	}
-
-	return defaultValue
+func Float64(name string, defaultValue float64, opts ...Option) float64 {
+	return std.Float64(name, defaultValue, opts...)
 }
 
 // Float64Strict extracts float64 value from environment variable named name
 // and returns defaultValue if it is absent. If the environment variable
 // can not be parsed, the method returns an error
-func Float64Strict(name string, defaultValue float64) (float64, error) {
-	if strVal, ok := os.LookupEnv(name); ok {
-		f, err := strconv.ParseFloat(strVal, 64)
-		if err != nil {
-			return 0, err
-		}
-
-		return f, nil
-	}
-
-	return defaultValue, nil
+func Float64Strict(name string, defaultValue float64, opts ...Option) (float64, error) {
+	return std.Float64Strict(name, defaultValue, opts...)
 }
 
 // Int extracts int value from environment variable named name
 // and returns defaultValue if it is absent or can not be parsed
-func Int(name string, defaultValue int) int {
-	if strVal, ok := os.LookupEnv(name); ok {
-		if i64, err := strconv.ParseInt(strVal, 10, 0); err == nil {
-			return int(i64)
-		}
-	}
-
-	return defaultValue
+func Int(name string, defaultValue int, opts ...Option) int {
+	return std.Int(name, defaultValue, opts...)
 }
 
 // IntStrict extracts int value from environment variable named name
 // and returns defaultValue if it is absent. If the environment variable
 // can not be parsed, the method returns an error
-func IntStrict(name string, defaultValue int) (int, error) {
-	if strVal, ok := os.LookupEnv(name); ok {
-		i64, err := strconv.ParseInt(strVal, 10, 0)
-		if err != nil {
-			return 0, err
-		}
-
-		return int(i64), nil
-	}
-
-	return defaultValue, nil
+func IntStrict(name string, defaultValue int, opts ...Option) (int, error) {
+	return std.IntStrict(name, defaultValue, opts...)
 }
 
 // Int64 extracts int64 value from environment variable named name
 // and returns defaultValue if it is absent or can not be parsed
-func Int64(name string, defaultValue int64) int64 {
-	if strVal, ok := os.LookupEnv(name); ok {
-		if i64, err := strconv.ParseInt(strVal, 10, 64); err == nil {
-			return i64
-		}
-	}
-
-	return defaultValue
+func Int64(name string, defaultValue int64, opts ...Option) int64 {
+	return std.Int64(name, defaultValue, opts...)
 }
 
 // Int64Strict extracts int64 value from environment variable named name
 // and returns defaultValue if it is absent. If the environment variable
 // can not be parsed, the method returns an error
-func Int64Strict(name string, defaultValue int64) (int64, error) {
-	if strVal, ok := os.LookupEnv(name); ok {
-		i64, err := strconv.ParseInt(strVal, 10, 64)
-		if err != nil {
-			return 0, err
-		}
-
-		return i64, nil
-	}
-
-	return defaultValue, nil
+func Int64Strict(name string, defaultValue int64, opts ...Option) (int64, error) {
+	return std.Int64Strict(name, defaultValue, opts...)
 }
 
 // String extracts string value from environment variable named name
 // and returns defaultValue if it is absent or can not be parsed
-func String(name, defaultValue string) string {
-	if val, ok := os.LookupEnv(name); ok {
-		return val
-	}
-	return defaultValue
+func String(name, defaultValue string, opts ...Option) string {
+	return std.String(name, defaultValue, opts...)
 }
 
-// Uint extracts uint value from environment variable named name
-// and returns defaultValue if it is absent or can not be parsed
-func Uint(name string, defaultValue uint) uint {
-	if strVal, ok := os.LookupEnv(name); ok {
-		if i64, err := strconv.ParseUint(strVal, 10, 0); err == nil {
-			return uint(i64)
-		} // Bool extracts bool value from environment variable named name
-		// and returns defaultValue if it is absent or can not be parsed
+// NonEmptyString extracts string value from environment variable named name
+// and returns defaultValue if it is absent or set to an empty string
+func NonEmptyString(name, defaultValue string, opts ...Option) string {
+	return std.NonEmptyString(name, defaultValue, opts...)
+}
 
-	}
+// NonEmptyStringStrict extracts string value from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// is set to an empty string, the method returns an error
+func NonEmptyStringStrict(name, defaultValue string, opts ...Option) (string, error) {
+	return std.NonEmptyStringStrict(name, defaultValue, opts...)
+}
 
-	return defaultValue
+// Uint extracts uint value from environment variable named name
+// and returns defaultValue if it is absent or can not be parsed
+func Uint(name string, defaultValue uint, opts ...Option) uint {
+	return std.Uint(name, defaultValue, opts...)
 }
 
 // UintStrict extracts uint value from environment variable named name
 // and returns defaultValue if it is absent. If the environment variable
 // can not be parsed, the method returns an error
-func UintStrict(name string, defaultValue uint) (uint, error) {
-	if strVal, ok := os.LookupEnv(name); ok {
-		i64, err := strconv.ParseUint(strVal, 10, 0)
-		if err != nil {
-			return 0, err
-		}
-
-		return uint(i64), nil
-	}
-
-	return defaultValue, nil
+func UintStrict(name string, defaultValue uint, opts ...Option) (uint, error) {
+	return std.UintStrict(name, defaultValue, opts...)
 }
 
 // Uint64 extracts uint64 value from environment variable named name
 // and returns defaultValue if it is absent or can not be parsed
-func Uint64(name string, defaultValue uint64) uint64 {
-	if strVal, ok := os.LookupEnv(name); ok {
-		if i64, err := strconv.ParseUint(strVal, 10, 64); err == nil {
-			return i64
-		}
-	}
-
-	return defaultValue
+func Uint64(name string, defaultValue uint64, opts ...Option) uint64 {
+	return std.Uint64(name, defaultValue, opts...)
 }
 
 // Uint64Strict extracts uint64 value from environment variable named name
 // and returns defaultValue if it is absent. If the environment variable
 // can not be parsed, the method returns an error
-func Uint64Strict(name string, defaultValue uint64) (uint64, error) {
-	if strVal, ok := os.LookupEnv(name); ok {
-		i64, err := strconv.ParseUint(strVal, 10, 64)
-		if err != nil {
-			return 0, err
-		}
-
-		return i64, nil
-	}
-
-	return defaultValue, nil
+func Uint64Strict(name string, defaultValue uint64, opts ...Option) (uint64, error) {
+	return std.Uint64Strict(name, defaultValue, opts...)
 }
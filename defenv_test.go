@@ -108,7 +108,7 @@ func TestBoolStrict(t *testing.T) {
 			envValue:     "",
 			defaultValue: true,
 			expRes:       false,
-			expErr:       errors.New(`strconv.ParseBool: parsing "": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="" as bool: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is "bad"`,
@@ -116,7 +116,7 @@ func TestBoolStrict(t *testing.T) {
 			envValue:     "bad",
 			defaultValue: true,
 			expRes:       false,
-			expErr:       errors.New(`strconv.ParseBool: parsing "bad": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="bad" as bool: invalid syntax`),
 		},
 		{
 			name:         `false then environment value is "F"`,
@@ -269,21 +269,21 @@ func TestDurationStrict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "30",
 			defaultValue: 3 * time.Second,
-			expErr:       errors.New("time: missing unit in duration 30"),
+			expErr:       errors.New(`defenv: parse VALUE="30" as time.Duration: time: missing unit in duration "30"`),
 		},
 		{
 			name:         `fail then environment value is ""`,
 			setEnv:       true,
 			envValue:     "",
 			defaultValue: 3 * time.Second,
-			expErr:       errors.New("time: invalid duration "),
+			expErr:       errors.New(`defenv: parse VALUE="" as time.Duration: time: invalid duration ""`),
 		},
 		{
 			name:         `fail then environment is "bad"`,
 			setEnv:       true,
 			envValue:     "bad",
 			defaultValue: 3 * time.Second,
-			expErr:       errors.New("time: invalid duration bad"),
+			expErr:       errors.New(`defenv: parse VALUE="bad" as time.Duration: time: invalid duration "bad"`),
 		},
 		{
 			name:         `use default value then environment value is not set`,
@@ -436,14 +436,14 @@ func TestFloat64Strict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "",
 			defaultValue: 1.2,
-			expErr:       errors.New(`strconv.ParseFloat: parsing "": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="" as float64: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is "bad"`,
 			setEnv:       true,
 			envValue:     "bad",
 			defaultValue: 1.2,
-			expErr:       errors.New(`strconv.ParseFloat: parsing "bad": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="bad" as float64: invalid syntax`),
 		},
 		{
 			name:         `use default value then environment value is not set`,
@@ -589,7 +589,7 @@ func TestIntStrict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "3.1",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseInt: parsing "3.1": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="3.1" as int: invalid syntax`),
 		},
 		{
 			name:         `0 then environment value is "0"`,
@@ -603,21 +603,21 @@ func TestIntStrict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseInt: parsing "": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="" as int: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is "bad"`,
 			setEnv:       true,
 			envValue:     "bad",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseInt: parsing "bad": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="bad" as int: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is more then then int max value`,
 			setEnv:       true,
 			envValue:     "12345678901234567890",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseInt: parsing "12345678901234567890": value out of range`),
+			expErr:       errors.New(`defenv: parse VALUE="12345678901234567890" as int: value out of range`),
 		},
 		{
 			name:         `use default value then environment value is not set`,
@@ -763,7 +763,7 @@ func TestInt64Strict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "3.1",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseInt: parsing "3.1": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="3.1" as int64: invalid syntax`),
 		},
 		{
 			name:         `0 then environment value is "0"`,
@@ -777,21 +777,21 @@ func TestInt64Strict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseInt: parsing "": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="" as int64: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is "bad"`,
 			setEnv:       true,
 			envValue:     "bad",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseInt: parsing "bad": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="bad" as int64: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is more then then int max value`,
 			setEnv:       true,
 			envValue:     "12345678901234567890",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseInt: parsing "12345678901234567890": value out of range`),
+			expErr:       errors.New(`defenv: parse VALUE="12345678901234567890" as int64: value out of range`),
 		},
 		{
 			name:         `use default value then environment value is not set`,
@@ -874,6 +874,110 @@ func TestString(t *testing.T) {
 	}
 }
 
+func TestNonEmptyString(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		setEnv       bool
+		envValue     string
+		defaultValue string
+		expRes       string
+	}{
+		{
+			name:         `"test" then environment value is "test"`,
+			setEnv:       true,
+			envValue:     "test",
+			defaultValue: "default",
+			expRes:       "test",
+		},
+		{
+			name:         `use default value then environment value is ""`,
+			setEnv:       true,
+			envValue:     "",
+			defaultValue: "default",
+			expRes:       "default",
+		},
+		{
+			name:         "use default value then environment value is not set",
+			setEnv:       false,
+			defaultValue: "default",
+			expRes:       "default",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if err := os.Unsetenv("VALUE"); err != nil {
+					t.Errorf("coudn't unset VALUE: %s", err)
+				}
+			}()
+
+			if tc.setEnv {
+				if err := os.Setenv("VALUE", tc.envValue); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res := NonEmptyString("VALUE", tc.defaultValue)
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestNonEmptyStringStrict(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		setEnv       bool
+		envValue     string
+		defaultValue string
+		expRes       string
+		expErr       error
+	}{
+		{
+			name:         `"test" from environment as "test"`,
+			setEnv:       true,
+			envValue:     "test",
+			defaultValue: "default",
+			expRes:       "test",
+		},
+		{
+			name:         `fail then environment value is ""`,
+			setEnv:       true,
+			envValue:     "",
+			defaultValue: "default",
+			expErr:       errors.New(`defenv: VALUE is set to an empty string`),
+		},
+		{
+			name:         "use default value then environment value is not set",
+			setEnv:       false,
+			defaultValue: "default",
+			expRes:       "default",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if err := os.Unsetenv("VALUE"); err != nil {
+					t.Errorf("coudn't unset VALUE: %s", err)
+				}
+			}()
+
+			if tc.setEnv {
+				if err := os.Setenv("VALUE", tc.envValue); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res, err := NonEmptyStringStrict("VALUE", tc.defaultValue)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
+
 func TestUint(t *testing.T) {
 	for _, tc := range []struct {
 		name         string
@@ -987,14 +1091,14 @@ func TestUintStrict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "-1",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "-1": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="-1" as uint: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is "3.1"`,
 			setEnv:       true,
 			envValue:     "3.1",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "3.1": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="3.1" as uint: invalid syntax`),
 		},
 		{
 			name:         `0 then environment value is "0"`,
@@ -1008,14 +1112,14 @@ func TestUintStrict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="" as uint: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is "bad"`,
 			setEnv:       true,
 			envValue:     "bad",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "bad": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="bad" as uint: invalid syntax`),
 		},
 		{
 			name:         `12345678901234567890 then environment value is "12345678901234567890"`,
@@ -1029,7 +1133,7 @@ func TestUintStrict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "123456789012345678901",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "123456789012345678901": value out of range`),
+			expErr:       errors.New(`defenv: parse VALUE="123456789012345678901" as uint: value out of range`),
 		},
 		{
 			name:         `use default value then environment value is not set`,
@@ -1175,14 +1279,14 @@ func TestUint64Strict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "-1",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "-1": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="-1" as uint64: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is "3.1"`,
 			setEnv:       true,
 			envValue:     "3.1",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "3.1": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="3.1" as uint64: invalid syntax`),
 		},
 		{
 			name:         `0 then environment value is "0"`,
@@ -1196,14 +1300,14 @@ func TestUint64Strict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="" as uint64: invalid syntax`),
 		},
 		{
 			name:         `fail then environment value is "bad"`,
 			setEnv:       true,
 			envValue:     "bad",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "bad": invalid syntax`),
+			expErr:       errors.New(`defenv: parse VALUE="bad" as uint64: invalid syntax`),
 		},
 		{
 			name:         `12345678901234567890 then environment value is "12345678901234567890"`,
@@ -1217,7 +1321,7 @@ func TestUint64Strict(t *testing.T) {
 			setEnv:       true,
 			envValue:     "123456789012345678901",
 			defaultValue: 321,
-			expErr:       errors.New(`strconv.ParseUint: parsing "123456789012345678901": value out of range`),
+			expErr:       errors.New(`defenv: parse VALUE="123456789012345678901" as uint64: value out of range`),
 		},
 		{
 			name:         `use default value then environment value is not set`,
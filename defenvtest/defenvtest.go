@@ -0,0 +1,56 @@
+// Package defenvtest contains helpers for testing code that reads
+// configuration through defenv.
+package defenvtest
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// ForbidOSMutation records the process environment and fails the test
+// if the environment has been changed when the test and its subtests
+// complete. Code under test should read variables through an injected
+// defenv.Env instead of calling os.Setenv or os.Unsetenv directly.
+//
+// defenvtest.ForbidOSMutation(t)
+//
+// Changes that are reverted before the test completes can not be detected
+func ForbidOSMutation(t testing.TB) {
+	before := environ()
+
+	t.Cleanup(func() {
+		after := environ()
+
+		var changes []string
+		for name, val := range after {
+			if old, ok := before[name]; !ok {
+				changes = append(changes, "set "+name)
+			} else if old != val {
+				changes = append(changes, "changed "+name)
+			}
+		}
+		for name := range before {
+			if _, ok := after[name]; !ok {
+				changes = append(changes, "unset "+name)
+			}
+		}
+
+		if len(changes) > 0 {
+			sort.Strings(changes)
+			t.Errorf("process environment was modified: %s", strings.Join(changes, ", "))
+		}
+	})
+}
+
+func environ() map[string]string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			env[kv[:i]] = kv[i+1:]
+		}
+	}
+
+	return env
+}
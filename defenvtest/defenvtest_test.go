@@ -0,0 +1,85 @@
+package defenvtest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+type recorder struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func TestForbidOSMutation(t *testing.T) {
+	if err := os.Setenv("DEFENVTEST_CHANGED", "old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("DEFENVTEST_UNSET", "value"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		for _, name := range []string{"DEFENVTEST_CHANGED", "DEFENVTEST_UNSET", "DEFENVTEST_SET"} {
+			if err := os.Unsetenv(name); err != nil {
+				t.Errorf("coudn't unset %s: %s", name, err)
+			}
+		}
+	}()
+
+	for _, tc := range []struct {
+		name   string
+		mutate func() error
+		expErr []string
+	}{
+		{
+			name:   `no errors then environment is not modified`,
+			mutate: func() error { return nil },
+		},
+		{
+			name: `no errors then modification is reverted`,
+			mutate: func() error {
+				if err := os.Setenv("DEFENVTEST_CHANGED", "new"); err != nil {
+					return err
+				}
+				return os.Setenv("DEFENVTEST_CHANGED", "old")
+			},
+		},
+		{
+			name: `error then environment is modified`,
+			mutate: func() error {
+				if err := os.Setenv("DEFENVTEST_SET", "value"); err != nil {
+					return err
+				}
+				if err := os.Setenv("DEFENVTEST_CHANGED", "new"); err != nil {
+					return err
+				}
+				return os.Unsetenv("DEFENVTEST_UNSET")
+			},
+			expErr: []string{"process environment was modified: changed DEFENVTEST_CHANGED, set DEFENVTEST_SET, unset DEFENVTEST_UNSET"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			ForbidOSMutation(r)
+			if err := tc.mutate(); err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range r.cleanups {
+				f()
+			}
+
+			if fmt.Sprint(r.errors) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected errors: %v, got: %v", tc.expErr, r.errors)
+			}
+		})
+	}
+}
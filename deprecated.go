@@ -0,0 +1,80 @@
+package defenv
+
+import (
+	"log"
+	"sync"
+)
+
+// Deprecated registers variable oldName as renamed to newName.
+// Reads of newName fall back to oldName if newName is absent,
+// and warn is called once the first time oldName is used.
+// If warn is nil, the warning is written to the standard logger
+func Deprecated(oldName, newName string, warn func(oldName, newName string)) {
+	std.Deprecated(oldName, newName, warn)
+}
+
+// Deprecated registers variable oldName as renamed to newName for the Env
+// and all Envs sharing its sources. Reads of newName fall back to oldName
+// if newName is absent, and warn is called once the first time oldName is used.
+// If warn is nil, the warning is written to the standard logger
+func (e *Env) Deprecated(oldName, newName string, warn func(oldName, newName string)) {
+	if warn == nil {
+		warn = logDeprecated
+	}
+
+	e.renames.add(e.prefix+oldName, e.prefix+newName, warn)
+}
+
+func logDeprecated(oldName, newName string) {
+	log.Printf("defenv: variable %s is deprecated, use %s instead", oldName, newName)
+}
+
+// renames maps new names of renamed variables to their old names.
+// It is shared by all copies of an Env
+type renames struct {
+	mu      sync.Mutex
+	renamed map[string]rename
+}
+
+type rename struct {
+	oldName string
+	warn    func(oldName, newName string)
+	warned  *bool
+}
+
+func newRenames() *renames {
+	return &renames{renamed: make(map[string]rename)}
+}
+
+func (r *renames) add(oldName, newName string, warn func(oldName, newName string)) {
+	r.mu.Lock()
+	r.renamed[newName] = rename{oldName: oldName, warn: warn, warned: new(bool)}
+	r.mu.Unlock()
+}
+
+// oldName returns the old name of variable named newName
+// or an empty string if the variable has not been renamed
+func (r *renames) oldName(newName string) string {
+	if r == nil {
+		return ""
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.renamed[newName].oldName
+}
+
+// used calls the warning function of variable named newName
+// if its old name is used for the first time
+func (r *renames) used(newName string) {
+	r.mu.Lock()
+	rn := r.renamed[newName]
+	first := !*rn.warned
+	*rn.warned = true
+	r.mu.Unlock()
+
+	if first {
+		rn.warn(rn.oldName, newName)
+	}
+}
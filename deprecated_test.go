@@ -0,0 +1,65 @@
+package defenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvDeprecated(t *testing.T) {
+	tt := []struct {
+		name     string
+		source   MapSource
+		expRes   int
+		expWarns []string
+	}{
+		{
+			name:     "new variable is set then new value and no warning",
+			source:   MapSource{"APP_WORKERS": "4", "APP_WORKER_NUMBER": "2"},
+			expRes:   4,
+			expWarns: nil,
+		},
+		{
+			name:     "only old variable is set then old value and one warning",
+			source:   MapSource{"APP_WORKER_NUMBER": "2"},
+			expRes:   2,
+			expWarns: []string{"APP_WORKER_NUMBER->APP_WORKERS"},
+		},
+		{
+			name:     "none is set then default value and no warning",
+			source:   MapSource{},
+			expRes:   8,
+			expWarns: nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var warns []string
+			env := NewEnv(tc.source).WithPrefix("APP_")
+			env.Deprecated("WORKER_NUMBER", "WORKERS", func(oldName, newName string) {
+				warns = append(warns, oldName+"->"+newName)
+			})
+
+			for i := 0; i < 2; i++ {
+				if res := env.Int("WORKERS", 8); res != tc.expRes {
+					t.Fatalf("expected result: %d, got: %d", tc.expRes, res)
+				}
+			}
+
+			if !reflect.DeepEqual(warns, tc.expWarns) {
+				t.Errorf("expected warnings: %v, got: %v", tc.expWarns, warns)
+			}
+		})
+	}
+}
+
+func TestEnvDeprecatedParseError(t *testing.T) {
+	env := NewEnv(MapSource{"OLD": "abc"})
+	env.Deprecated("OLD", "NEW", func(string, string) {})
+
+	_, err := env.IntStrict("NEW", 0)
+	expErr := `defenv: parse OLD="abc" as int: invalid syntax`
+	if err == nil || err.Error() != expErr {
+		t.Errorf("expected error: %s, got: %v", expErr, err)
+	}
+}
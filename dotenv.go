@@ -0,0 +1,204 @@
+package defenv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// DotEnvSource reads a file in dotenv format and returns its variables
+// as a Source. The process environment is not modified.
+//
+// The file consists of KEY=VALUE lines, optionally prefixed with "export".
+// Empty lines and lines starting with # are ignored. Unquoted values are
+// trimmed and may be followed by a # comment. Values in single quotes are
+// taken literally, values in double quotes support \n, \r, \t, \" and \\
+// escapes. Quoted values may span several lines
+func DotEnvSource(path string) (MapSource, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := parseDotEnv(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("defenv: %s: %s", path, err)
+	}
+
+	return src, nil
+}
+
+// ReaderSource reads variables in dotenv format from r and returns them
+// as a Source. See DotEnvSource for the description of the format
+func ReaderSource(r io.Reader) (MapSource, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := parseDotEnv(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("defenv: %s", err)
+	}
+
+	return src, nil
+}
+
+// DotEnvChain reads several dotenv files and merges their variables
+// into one Source. Files listed later take precedence over earlier ones,
+// files that do not exist are skipped.
+//
+// src, err := defenv.DotEnvChain(".env", ".env.local", ".env."+appEnv)
+func DotEnvChain(paths ...string) (MapSource, error) {
+	merged := MapSource{}
+	for _, path := range paths {
+		src, err := DotEnvSource(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for name, val := range src {
+			merged[name] = val
+		}
+	}
+
+	return merged, nil
+}
+
+func parseDotEnv(data string) (MapSource, error) {
+	src := MapSource{}
+	line := 1
+
+	for len(data) > 0 {
+		start := line
+
+		// take the key
+		eol := strings.IndexByte(data, '\n')
+		if eol < 0 {
+			eol = len(data)
+		}
+
+		head := strings.TrimSpace(data[:eol])
+		if head == "" || head[0] == '#' {
+			data = skipLine(data, eol)
+			line++
+			continue
+		}
+
+		eq := strings.IndexByte(data[:eol], '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: missing =", start)
+		}
+
+		key := strings.TrimSpace(data[:eq])
+		if strings.HasPrefix(key, "export ") || strings.HasPrefix(key, "export\t") {
+			key = strings.TrimSpace(key[len("export"):])
+		}
+		if !isName(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", start, key)
+		}
+
+		// take the value
+		data = strings.TrimLeft(data[eq+1:], " \t")
+
+		var (
+			val  string
+			rest string
+			err  error
+		)
+
+		switch {
+		case strings.HasPrefix(data, "'"):
+			end := strings.IndexByte(data[1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quote", start)
+			}
+			val, rest = data[1:1+end], data[2+end:]
+		case strings.HasPrefix(data, `"`):
+			val, rest, err = unquoteDouble(data[1:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", start, err)
+			}
+		default:
+			eol = strings.IndexByte(data, '\n')
+			if eol < 0 {
+				eol = len(data)
+			}
+			val, rest = data[:eol], data[eol:]
+			if i := strings.Index(val, " #"); i >= 0 {
+				val = val[:i]
+			}
+			if i := strings.Index(val, "\t#"); i >= 0 {
+				val = val[:i]
+			}
+			val = strings.TrimSpace(val)
+		}
+
+		line += strings.Count(data[:len(data)-len(rest)], "\n")
+
+		// only a comment may follow the value
+		eol = strings.IndexByte(rest, '\n')
+		if eol < 0 {
+			eol = len(rest)
+		}
+		if tail := strings.TrimSpace(rest[:eol]); tail != "" && tail[0] != '#' {
+			return nil, fmt.Errorf("line %d: unexpected %q after value", line, tail)
+		}
+
+		src[key] = val
+		data = skipLine(rest, eol)
+		line++
+	}
+
+	return src, nil
+}
+
+// skipLine returns data after the end of line at position eol
+func skipLine(data string, eol int) string {
+	if eol < len(data) {
+		return data[eol+1:]
+	}
+
+	return ""
+}
+
+// unquoteDouble unescapes a double-quoted value up to the closing quote
+// and returns it together with the remaining data
+func unquoteDouble(data string) (string, string, error) {
+	var buf bytes.Buffer
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; c {
+		case '"':
+			return buf.String(), data[i+1:], nil
+		case '\\':
+			i++
+			if i == len(data) {
+				break
+			}
+			switch data[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case '"', '\\', '$':
+				buf.WriteByte(data[i])
+			default:
+				buf.WriteByte('\\')
+				buf.WriteByte(data[i])
+			}
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return "", "", errors.New("unterminated double quote")
+}
@@ -0,0 +1,239 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		data   string
+		expRes MapSource
+		expErr error
+	}{
+		{
+			name: `simple values, comments and empty lines`,
+			data: "# comment\n\nHOST=localhost\n  PORT = 8080  \r\nEMPTY=\n",
+			expRes: MapSource{
+				"HOST":  "localhost",
+				"PORT":  "8080",
+				"EMPTY": "",
+			},
+		},
+		{
+			name:   `export prefix`,
+			data:   "export TOKEN=abc\nexport\tID=1",
+			expRes: MapSource{"TOKEN": "abc", "ID": "1"},
+		},
+		{
+			name: `inline comments`,
+			data: "A=1 # one\nB=2#two\nC='3' # three\nD=\"4\"\t# four",
+			expRes: MapSource{
+				"A": "1",
+				"B": "2#two",
+				"C": "3",
+				"D": "4",
+			},
+		},
+		{
+			name: `quoted values`,
+			data: `SINGLE='a \n $b "c"'` + "\n" + `DOUBLE="a\n\tb \"c\" \\ \$d \x"`,
+			expRes: MapSource{
+				"SINGLE": `a \n $b "c"`,
+				"DOUBLE": "a\n\tb \"c\" \\ $d \\x",
+			},
+		},
+		{
+			name: `multiline values`,
+			data: "KEY=\"-----BEGIN KEY-----\nabc\n-----END KEY-----\"\nCERT='line1\nline2'\nNEXT=1",
+			expRes: MapSource{
+				"KEY":  "-----BEGIN KEY-----\nabc\n-----END KEY-----",
+				"CERT": "line1\nline2",
+				"NEXT": "1",
+			},
+		},
+		{
+			name:   `later value overrides earlier`,
+			data:   "A=1\nA=2",
+			expRes: MapSource{"A": "2"},
+		},
+		{
+			name:   `fail then line has no =`,
+			data:   "A=1\nBAD\n",
+			expErr: errors.New(`line 2: missing =`),
+		},
+		{
+			name:   `fail then variable name is invalid`,
+			data:   "1A=1",
+			expErr: errors.New(`line 1: invalid variable name "1A"`),
+		},
+		{
+			name:   `fail then single quote is not terminated`,
+			data:   "A='1\nB=2",
+			expErr: errors.New(`line 1: unterminated single quote`),
+		},
+		{
+			name:   `fail then double quote is not terminated`,
+			data:   "A=1\nB=\"2",
+			expErr: errors.New(`line 2: unterminated double quote`),
+		},
+		{
+			name:   `fail then there is garbage after quoted value`,
+			data:   "A=\"multi\nline\" garbage",
+			expErr: errors.New(`line 2: unexpected "garbage" after value`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := parseDotEnv(tc.data)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if tc.expErr == nil && !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %q, got: %q", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestDotEnvSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "defenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".env")
+	if err := ioutil.WriteFile(path, []byte("PORT=8080\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := DotEnvSource(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if res := NewEnv(src).Int("PORT", 80); res != 8080 {
+		t.Errorf("expected value: %d, got: %d", 8080, res)
+	}
+	if _, ok := os.LookupEnv("PORT"); ok {
+		t.Errorf("process environment is modified")
+	}
+
+	if err := ioutil.WriteFile(path, []byte("BAD\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	expErr := fmt.Sprintf("defenv: %s: line 1: missing =", path)
+	if _, err := DotEnvSource(path); fmt.Sprint(err) != expErr {
+		t.Errorf("expected error: %s, got: %v", expErr, err)
+	}
+
+	if _, err := DotEnvSource(filepath.Join(dir, "absent")); !os.IsNotExist(err) {
+		t.Errorf("expected not exist error, got: %v", err)
+	}
+}
+
+func TestReaderSource(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		r      io.Reader
+		expRes MapSource
+		expErr error
+	}{
+		{
+			name:   `variables from reader`,
+			r:      strings.NewReader("HOST=localhost\nPORT=8080\n"),
+			expRes: MapSource{"HOST": "localhost", "PORT": "8080"},
+		},
+		{
+			name:   `fail then data can not be parsed`,
+			r:      strings.NewReader("A=1\nB='2\n"),
+			expErr: errors.New(`defenv: line 2: unterminated single quote`),
+		},
+		{
+			name:   `fail then reader fails`,
+			r:      iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("A=1"))),
+			expErr: iotest.ErrTimeout,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := ReaderSource(tc.r)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if tc.expErr == nil && !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %q, got: %q", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestDotEnvChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "defenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, data := range map[string]string{
+		".env":            "HOST=localhost\nPORT=8080\nDEBUG=false\n",
+		".env.local":      "PORT=9090\n",
+		".env.production": "DEBUG=true\n",
+		".env.bad":        "BAD\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		paths  []string
+		expRes MapSource
+		expErr error
+	}{
+		{
+			name:   `later files take precedence`,
+			paths:  []string{".env", ".env.local", ".env.production"},
+			expRes: MapSource{"HOST": "localhost", "PORT": "9090", "DEBUG": "true"},
+		},
+		{
+			name:   `absent files are skipped`,
+			paths:  []string{".env", ".env.absent"},
+			expRes: MapSource{"HOST": "localhost", "PORT": "8080", "DEBUG": "false"},
+		},
+		{
+			name:   `empty source then no files exist`,
+			paths:  []string{".env.absent"},
+			expRes: MapSource{},
+		},
+		{
+			name:   `fail then a file can not be parsed`,
+			paths:  []string{".env", ".env.bad"},
+			expErr: fmt.Errorf("defenv: %s: line 1: missing =", filepath.Join(dir, ".env.bad")),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			paths := make([]string, len(tc.paths))
+			for i, p := range tc.paths {
+				paths[i] = filepath.Join(dir, p)
+			}
+
+			res, err := DotEnvChain(paths...)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if tc.expErr == nil && !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %q, got: %q", tc.expRes, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package defenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// readLog records reads of variables for Dump and Accessed.
+// It is shared by all copies of an Env
+type readLog struct {
+	mu       sync.RWMutex
+	entries  map[string]readEntry
+	accessed map[string]bool // names of all variables looked up, present or not
+	frozen   bool            // reads of variables not in accessed are forbidden
+	checks   map[string]func(env *Env) error
+}
+
+type readEntry struct {
+	raw       string   // value read from the source
+	def       defValue // default value, used if defaulted is true
+	source    string   // description of the source, empty for defaults
+	defaulted bool
+	invalid   bool // the value can not be parsed and the default is used
+}
+
+func (r readEntry) equal(other readEntry) bool {
+	return r.raw == other.raw && r.source == other.source && r.defaulted == other.defaulted &&
+		r.invalid == other.invalid && r.def.equal(other.def)
+}
+
+// defValue is a default value of a getter. Scalars are kept unboxed,
+// so recording a read of a variable does not allocate
+type defValue struct {
+	kind  defKind
+	bits  uint64      // bool, integer, float64 and time.Duration values
+	str   string      // string values
+	other interface{} // values of other types
+}
+
+type defKind uint8
+
+const (
+	noDefault defKind = iota
+	boolDefault
+	intDefault
+	int64Default
+	uintDefault
+	uint64Default
+	float64Default
+	durationDefault
+	stringDefault
+	otherDefault
+)
+
+func boolDef(b bool) defValue {
+	var bits uint64
+	if b {
+		bits = 1
+	}
+	return defValue{kind: boolDefault, bits: bits}
+}
+
+func intDef(i int) defValue                { return defValue{kind: intDefault, bits: uint64(i)} }
+func int64Def(i int64) defValue            { return defValue{kind: int64Default, bits: uint64(i)} }
+func uintDef(u uint) defValue              { return defValue{kind: uintDefault, bits: uint64(u)} }
+func uint64Def(u uint64) defValue          { return defValue{kind: uint64Default, bits: u} }
+func float64Def(f float64) defValue        { return defValue{kind: float64Default, bits: math.Float64bits(f)} }
+func durationDef(d time.Duration) defValue { return defValue{kind: durationDefault, bits: uint64(d)} }
+func stringDef(s string) defValue          { return defValue{kind: stringDefault, str: s} }
+
+// otherDef returns a default value of any other type, e.g. []string
+func otherDef(v interface{}) defValue {
+	return defValue{kind: otherDefault, other: v}
+}
+
+// isSet reports whether the getter has a default value
+func (d defValue) isSet() bool {
+	return d.kind != noDefault
+}
+
+// value returns the default value boxed in an interface value
+func (d defValue) value() interface{} {
+	switch d.kind {
+	case boolDefault:
+		return d.bits != 0
+	case intDefault:
+		return int(d.bits)
+	case int64Default:
+		return int64(d.bits)
+	case uintDefault:
+		return uint(d.bits)
+	case uint64Default:
+		return d.bits
+	case float64Default:
+		return math.Float64frombits(d.bits)
+	case durationDefault:
+		return time.Duration(d.bits)
+	case stringDefault:
+		return d.str
+	case otherDefault:
+		return d.other
+	}
+
+	return nil
+}
+
+func (d defValue) equal(other defValue) bool {
+	if d.kind == otherDefault && other.kind == otherDefault {
+		return reflect.DeepEqual(d.other, other.other)
+	}
+
+	return d.kind == other.kind && d.bits == other.bits && d.str == other.str
+}
+
+// text returns the value of variable named name as a string
+// and reports whether it should be quoted. Values are formatted lazily,
+// so recording a read does not format them
+func (r readEntry) text(name string) (string, bool) {
+	if IsSensitive(name) {
+		return redacted, false
+	}
+
+	if !r.defaulted {
+		return r.raw, true
+	}
+
+	if r.def.kind == stringDefault {
+		return r.def.str, true
+	}
+
+	return fmt.Sprint(r.def.value()), false
+}
+
+// format returns the value of variable named name, quoted if it is a string
+func (r readEntry) format(name string) string {
+	s, quoted := r.text(name)
+	if quoted {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+func newReadLog() *readLog {
+	return &readLog{entries: make(map[string]readEntry), accessed: make(map[string]bool), checks: make(map[string]func(env *Env) error)}
+}
+
+// access records that variable named name has been looked up.
+// If the log is frozen and the variable has not been looked up before,
+// an error is returned
+func (l *readLog) access(name string) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen && !l.accessed[name] {
+		return &frozenError{name: name}
+	}
+	l.accessed[name] = true
+
+	return nil
+}
+
+func (l *readLog) record(name string, entry readEntry) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	l.entries[name] = entry
+	l.mu.Unlock()
+}
+
+// seen reports whether variable named name has been looked up and returns
+// its last recorded read, if any. Repeated reads usually see the same value,
+// so value skips access and record, which take the write lock, after it
+func (l *readLog) seen(name string) (accessed bool, last readEntry, logged bool) {
+	if l == nil {
+		return true, readEntry{}, false
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	last, logged = l.entries[name]
+	return l.accessed[name], last, logged
+}
+
+// invalidate marks the last read of variable named name as invalid
+func (l *readLog) invalidate(name string) {
+	if l == nil || name == "" {
+		return
+	}
+
+	l.mu.Lock()
+	if entry, ok := l.entries[name]; ok {
+		entry.invalid = true
+		l.entries[name] = entry
+	}
+	l.mu.Unlock()
+}
+
+// entry returns the last read of variable named name
+func (l *readLog) entry(name string) readEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.entries[name]
+}
+
+// snapshot returns names of read variables in sorted order and their entries
+func (l *readLog) snapshot() ([]string, map[string]readEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	names := make([]string, 0, len(l.entries))
+	entries := make(map[string]readEntry, len(l.entries))
+	for name, entry := range l.entries {
+		names = append(names, name)
+		entries[name] = entry
+	}
+	sort.Strings(names)
+
+	return names, entries
+}
+
+// Dump writes variables read by the process environment getters so far
+// with their values and sources, see Env.Dump
+func Dump(w io.Writer) error {
+	return std.Dump(w)
+}
+
+// Dump writes every variable read so far through the Env or its copies,
+// its last value and where the value came from: a source, a file named
+// by name_FILE variable or the default value passed to a getter. Values
+// ordinary getters failed to parse are marked as invalid.
+// Values of sensitive variables are redacted
+func (e *Env) Dump(w io.Writer) error {
+	names, entries := e.reads.snapshot()
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVALUE\tSOURCE")
+	for _, name := range names {
+		entry := entries[name]
+		source := entry.source
+		if entry.defaulted {
+			source = "default"
+		}
+		if entry.invalid {
+			source += ", invalid, default used"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, entry.format(name), source)
+	}
+
+	return tw.Flush()
+}
+
+// DumpJSON returns variables read by the process environment getters
+// so far as a JSON document, see Env.DumpJSON
+func DumpJSON() ([]byte, error) {
+	return std.DumpJSON()
+}
+
+// DumpJSON returns the same information as Dump as a JSON array
+// of objects sorted by name, e.g. for a /debug/config endpoint:
+//
+// [{"name":"PORT","value":"8080","source":"os"},{"name":"TIMEOUT","value":"5s","default":true}]
+func (e *Env) DumpJSON() ([]byte, error) {
+	type variable struct {
+		Name    string `json:"name"`
+		Value   string `json:"value"`
+		Source  string `json:"source,omitempty"`
+		Default bool   `json:"default,omitempty"`
+		Invalid bool   `json:"invalid,omitempty"`
+	}
+
+	names, entries := e.reads.snapshot()
+	vars := make([]variable, len(names))
+	for i, name := range names {
+		entry := entries[name]
+		value, _ := entry.text(name)
+		vars[i] = variable{
+			Name:    name,
+			Value:   value,
+			Source:  entry.source,
+			Default: entry.defaulted || entry.invalid,
+			Invalid: entry.invalid,
+		}
+	}
+
+	return json.Marshal(vars)
+}
+
+// Accessed returns sorted names of all environment variables
+// the process has read through the package level functions,
+// including absent ones
+func Accessed() []string {
+	return std.Accessed()
+}
+
+// Accessed returns sorted names of all variables read
+// through the Env or its copies, including absent ones
+func (e *Env) Accessed() []string {
+	e.reads.mu.Lock()
+	names := make([]string, 0, len(e.reads.accessed))
+	for name := range e.reads.accessed {
+		names = append(names, name)
+	}
+	e.reads.mu.Unlock()
+
+	sort.Strings(names)
+
+	return names
+}
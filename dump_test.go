@@ -0,0 +1,96 @@
+package defenv
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEnvDump(t *testing.T) {
+	defer resetSensitive()
+
+	dir, err := ioutil.TempDir("", "defenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "password")
+	if err := ioutil.WriteFile(path, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	MarkSensitive("DB_PASSWORD")
+
+	env := NewEnv(MapSource{"PORT": "8080", "DB_PASSWORD_FILE": path, "WORKERS": "many"}).WithFileFallback()
+	env.Int("PORT", 80)
+	env.String("DB_PASSWORD", "")
+	env.Duration("TIMEOUT", 5*time.Second)
+	env.WithPrefix("APP_").String("MODE", "dev")
+	env.Int("WORKERS", 8)
+	env.Command("CMD", []string{"run"})
+
+	var buf bytes.Buffer
+	if err := env.Dump(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expRes := `NAME         VALUE       SOURCE
+APP_MODE     "dev"       default
+CMD          [run]       default
+DB_PASSWORD  [REDACTED]  file ` + path + `
+PORT         "8080"      defenv.MapSource
+TIMEOUT      5s          default
+WORKERS      "many"      defenv.MapSource, invalid, default used
+`
+	if buf.String() != expRes {
+		t.Errorf("expected dump:\n%s\ngot:\n%s", expRes, buf.String())
+	}
+}
+
+func TestEnvDumpJSON(t *testing.T) {
+	defer resetSensitive()
+
+	MarkSensitive("API_KEY")
+
+	env := NewEnv(MapSource{"PORT": "8080", "API_KEY": "s3cr3t", "WORKERS": "many"})
+	env.Int("PORT", 80)
+	env.String("API_KEY", "")
+	env.Duration("TIMEOUT", 5*time.Second)
+	env.Int("WORKERS", 8)
+
+	res, err := env.DumpJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expRes := `[{"name":"API_KEY","value":"[REDACTED]","source":"defenv.MapSource"},` +
+		`{"name":"PORT","value":"8080","source":"defenv.MapSource"},` +
+		`{"name":"TIMEOUT","value":"5s","default":true},` +
+		`{"name":"WORKERS","value":"many","source":"defenv.MapSource","default":true,"invalid":true}]`
+	if string(res) != expRes {
+		t.Errorf("expected JSON:\n%s\ngot:\n%s", expRes, res)
+	}
+
+	if res, err := NewEnv().DumpJSON(); err != nil || string(res) != "[]" {
+		t.Errorf("expected empty JSON array, got: %s (%v)", res, err)
+	}
+}
+
+func TestEnvAccessed(t *testing.T) {
+	env := NewEnv(MapSource{"APP_PORT": "80", "HOST": "localhost"})
+
+	env.WithPrefix("APP_").Int("PORT", 0)
+	env.String("HOST", "")
+	env.StringAny([]string{"ABSENT", "HOST"}, "")
+	env.Command("CMD", nil)
+
+	expNames := []string{"ABSENT", "APP_PORT", "CMD", "CMD_APPEND", "CMD_PREPEND", "HOST"}
+	if names := env.Accessed(); !reflect.DeepEqual(names, expNames) {
+		t.Errorf("expected names: %v, got: %v", expNames, names)
+	}
+}
@@ -0,0 +1,880 @@
+package defenv
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Env extracts variables from one or more sources. Sources are consulted
+// in the order they were passed to NewEnv, the first source containing
+// a variable wins. Package level functions use an Env reading
+// the process environment.
+//
+// Env is safe for concurrent use by multiple goroutines, provided its
+// sources are: sources of the package are, but a MapSource must not be
+// modified while it is read. Methods configuring an Env return copies,
+// state shared by the copies, such as the read log and the cache, is synchronized
+type Env struct {
+	source       Source
+	ctx          context.Context
+	prefix       string
+	fileFallback bool
+	trimSpace    bool
+	unquote      bool
+	emptyAsUnset bool
+	strict       bool
+	dryRun       bool
+	onError      func(error)
+	onRead       func(name, raw string, used Source, defaulted bool)
+	onFail       func(err error)
+	reads        *readLog
+	renames      *renames
+	conflicts    *conflicts
+	cache        *valueCache
+}
+
+// fileSuffix is appended to a variable name to get name of the variable
+// containing path to a file with the value
+const fileSuffix = "_FILE"
+
+// std is used by package level functions
+var std = NewEnv(OS)
+
+// NewEnv returns Env extracting variables from sources
+func NewEnv(sources ...Source) *Env {
+	return &Env{source: Layered(sources...), reads: newReadLog(), renames: newRenames(), conflicts: newConflicts()}
+}
+
+// New returns Env extracting variables with lookup function,
+// which has the signature of os.LookupEnv
+func New(lookup func(name string) (string, bool)) *Env {
+	return NewEnv(LookupFunc(lookup))
+}
+
+// FromEnviron returns Env extracting variables from environ, a list of
+// KEY=VALUE entries in the form returned by os.Environ. If a key occurs
+// several times, the last value is used, the same way as in exec.Cmd.Env.
+// Entries without = are ignored
+func FromEnviron(environ []string) *Env {
+	return NewEnv(MapSource(parseEnviron(environ)))
+}
+
+// parseEnviron returns variables of environ, a list of KEY=VALUE entries
+func parseEnviron(environ []string) map[string]string {
+	vars := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			vars[kv[:i]] = kv[i+1:]
+		}
+	}
+
+	return vars
+}
+
+// WithPrefix returns a copy of the Env prepending prefix to names of
+// all variables it reads, e.g. env.WithPrefix("REDIS_").String("HOST", "")
+// reads REDIS_HOST. Prefixes of nested calls are concatenated
+func (e *Env) WithPrefix(prefix string) *Env {
+	c := *e
+	c.prefix = e.prefix + prefix
+	return &c
+}
+
+// WithFileFallback returns a copy of the Env that reads value of a variable
+// from the file named by name_FILE variable if the variable itself is absent.
+// For example, if DB_PASSWORD is absent and DB_PASSWORD_FILE=/run/secrets/db,
+// the value of DB_PASSWORD is the content of /run/secrets/db without
+// trailing newlines. This convention is used by official Docker images
+func (e *Env) WithFileFallback() *Env {
+	c := *e
+	c.fileFallback = true
+	return &c
+}
+
+// WithTrimSpace returns a copy of the Env removing leading and trailing
+// white space from values before parsing them, e.g. trailing newlines
+// added by templating tools
+func (e *Env) WithTrimSpace() *Env {
+	c := *e
+	c.trimSpace = true
+	return &c
+}
+
+// WithUnquote returns a copy of the Env removing a pair of matching single
+// or double quotes around values, e.g. PORT="8080" copied from a dotenv
+// file into a real environment definition. Quotes are removed after
+// white space is trimmed, escape sequences are not processed
+func (e *Env) WithUnquote() *Env {
+	c := *e
+	c.unquote = true
+	return &c
+}
+
+// WithEmptyAsUnset returns a copy of the Env treating variables set to
+// an empty string as absent, so the default value is used instead.
+// The check is done after trimming and unquoting
+func (e *Env) WithEmptyAsUnset() *Env {
+	c := *e
+	c.emptyAsUnset = true
+	return &c
+}
+
+// WithContext returns a copy of the Env passing ctx to sources
+// implementing SourceContext, so lookups honour its deadline and cancellation.
+// Strict methods of the copy return an error if a lookup fails
+func (e *Env) WithContext(ctx context.Context) *Env {
+	c := *e
+	c.ctx = ctx
+	return &c
+}
+
+// Expander returns Expander resolving references from the Env
+func (e *Env) Expander() Expander {
+	return Expander{lookup: func(name string) (string, bool) {
+		if err := e.reads.access(e.prefix + name); err != nil {
+			return "", false
+		}
+
+		val, ok, _, err := e.lookup(name)
+		return val, ok && err == nil
+	}}
+}
+
+// Strict returns a copy of the Env in strict mode. In strict mode
+// ordinary methods return the default value only if a variable is absent
+// and panic if it can not be parsed, so a whole service can be switched
+// to fail fast without changing call sites
+func (e *Env) Strict(strict bool) *Env {
+	c := *e
+	c.strict = strict
+	return &c
+}
+
+// WithErrorHandler returns a copy of the Env in strict mode calling
+// handler instead of panicking if a variable can not be parsed.
+// If handler returns, ordinary methods return the default value
+func (e *Env) WithErrorHandler(handler func(error)) *Env {
+	c := *e
+	c.strict = true
+	c.onError = handler
+	return &c
+}
+
+// ExitOnError returns a copy of the Env in strict mode logging an error
+// and exiting with status 2 if a variable can not be parsed
+func (e *Env) ExitOnError() *Env {
+	return e.WithErrorHandler(exitOnError)
+}
+
+// exit is replaced in tests
+var exit = os.Exit
+
+func exitOnError(err error) {
+	log.Print(err)
+	exit(2)
+}
+
+// tracing reports whether reads through the Env are written to the debug trace
+func (e *Env) tracing() bool {
+	return !e.dryRun && debugging()
+}
+
+// fail is called by ordinary methods if a variable can not be read or parsed
+func (e *Env) fail(err error) {
+	if e.tracing() {
+		debugf("%v, default used", err)
+	}
+	e.reads.invalidate(errorVar(err))
+
+	if e.onFail != nil {
+		e.onFail(err)
+	}
+
+	if !e.strict {
+		return
+	}
+
+	if e.onError != nil {
+		e.onError(err)
+		return
+	}
+
+	panic(&failure{err: err})
+}
+
+// OnRead returns a copy of the Env calling hook on every read of a variable,
+// e.g. to log or audit configuration. The hook receives name of the variable,
+// its value before parsing and the source containing it. If the variable
+// is absent, defaulted is true and used is nil. If a getter reads several
+// variables, e.g. with Fallback, the hook is called once with the variable
+// that was used or with the first name if none of them is present
+func (e *Env) OnRead(hook func(name, raw string, used Source, defaulted bool)) *Env {
+	c := *e
+	c.onRead = hook
+	return &c
+}
+
+// value returns name and value of the first present variable of names.
+// Options are applied to the value. The read is recorded for Dump with def
+// as the value if none of the variables is present, unset def means that
+// the caller has no default value
+func (e *Env) value(names []string, def defValue, o options) (string, string, bool, error) {
+	if len(o.fallback) > 0 {
+		names = append(append([]string{}, names...), o.fallback...)
+	}
+	if len(names) > 0 {
+		e.conflicts.check(e.prefix+names[0], def)
+	}
+
+	var (
+		first       readEntry // the last read of the first variable
+		firstLogged bool
+	)
+	for i, name := range names {
+		name = e.prefix + name
+		accessed, last, logged := e.reads.seen(name)
+		if i == 0 {
+			first, firstLogged = last, logged
+		}
+		if !accessed {
+			if err := e.reads.access(name); err != nil {
+				return name, "", false, err
+			}
+		}
+
+		val, ok, src, err := e.resolve(name)
+		if err == nil && !ok {
+			if oldName := e.renames.oldName(name); oldName != "" {
+				if err := e.reads.access(oldName); err != nil {
+					return oldName, "", false, err
+				}
+
+				val, ok, src, err = e.resolve(oldName)
+				if ok {
+					if !e.dryRun {
+						e.renames.used(name)
+					}
+					name, logged = oldName, false
+				}
+			}
+		}
+		if err != nil {
+			if e.tracing() {
+				debugf("defenv: lookup %s: %v", name, err)
+			}
+			return name, "", false, err
+		}
+		if !ok {
+			if e.tracing() {
+				debugf("defenv: lookup %s: absent", name)
+			}
+			continue
+		}
+
+		if val, ok = clean(val, o.trimSpace, false, o.emptyAsUnset); !ok {
+			if e.tracing() {
+				debugf("defenv: lookup %s: empty, skipped", name)
+			}
+			continue
+		}
+
+		if e.tracing() {
+			debugf("defenv: lookup %s: %s from %s", name, quoteValue(name, val), sourceName(src))
+		}
+		if e.onRead != nil {
+			e.onRead(name, val, src, false)
+		}
+		if entry := (readEntry{raw: val, source: sourceName(src)}); !logged || !last.equal(entry) {
+			e.reads.record(name, entry)
+		}
+
+		return name, val, true, nil
+	}
+
+	if e.onRead != nil && len(names) > 0 {
+		e.onRead(e.prefix+names[0], "", nil, true)
+	}
+	if def.isSet() && len(names) > 0 {
+		entry := readEntry{def: def, defaulted: true}
+		if e.tracing() {
+			debugf("defenv: %s: default %s used", e.prefix+names[0], entry.format(e.prefix+names[0]))
+		}
+		if !firstLogged || !first.equal(entry) {
+			e.reads.record(e.prefix+names[0], entry)
+		}
+	}
+
+	if o.required {
+		prefixed := make([]string, len(names))
+		for i, name := range names {
+			prefixed[i] = e.prefix + name
+		}
+
+		return "", "", false, &notSetError{names: prefixed, suggestion: e.suggest(prefixed)}
+	}
+
+	return "", "", false, nil
+}
+
+// lookup returns value of variable named name, reports whether it is present
+// and returns the source containing it. An error is returned if the value
+// exists but can not be read
+func (e *Env) lookup(name string) (string, bool, Source, error) {
+	return e.lookupName(e.prefix + name)
+}
+
+// lookupName is like lookup, but name already contains the prefix
+func (e *Env) lookupName(name string) (string, bool, Source, error) {
+	val, ok, src, err := e.lookupRaw(name)
+	if err != nil || !ok {
+		return "", false, nil, err
+	}
+
+	val, ok = clean(val, e.trimSpace, e.unquote, e.emptyAsUnset)
+	if !ok {
+		return "", false, nil, nil
+	}
+
+	return val, true, src, nil
+}
+
+// clean removes white space and quotes around value val of a variable
+// if trimSpace and unquote are set, in this order, and reports whether
+// the variable is present: with emptyAsUnset an empty value is absent.
+// Env settings and getter options share it
+func clean(val string, trimSpace, unquote, emptyAsUnset bool) (string, bool) {
+	if trimSpace {
+		val = strings.TrimSpace(val)
+	}
+
+	if unquote && len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+		val = val[1 : len(val)-1]
+	}
+
+	if emptyAsUnset && val == "" {
+		return "", false
+	}
+
+	return val, true
+}
+
+// lookupRaw returns value of variable named name from the source or,
+// if file fallback is enabled, from the file named by name_FILE
+func (e *Env) lookupRaw(name string) (string, bool, Source, error) {
+	val, ok, src, err := e.lookupSource(name)
+	if err != nil || ok {
+		return val, ok, src, err
+	}
+
+	if e.fileFallback {
+		path, ok, _, err := e.lookupSource(name + fileSuffix)
+		if err != nil {
+			return "", false, nil, err
+		}
+		if ok {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return "", false, nil, &varError{
+					name: name,
+					code: CodeFileError,
+					msg:  fmt.Sprintf("defenv: %s is not readable from %s: %v", name, name+fileSuffix, err),
+					err:  err,
+				}
+			}
+
+			return strings.TrimRight(string(data), "\r\n"), true, fileSource(path), nil
+		}
+	}
+
+	return "", false, nil, nil
+}
+
+// lookupSource returns value of variable named name
+// and the source containing it
+func (e *Env) lookupSource(name string) (string, bool, Source, error) {
+	if l, ok := e.source.(layered); ok {
+		return l.lookup(e.ctx, name)
+	}
+
+	if e.ctx != nil {
+		if src, ok := e.source.(SourceContext); ok {
+			val, ok, err := src.LookupContext(e.ctx, name)
+			return val, ok, e.source, err
+		}
+	}
+
+	val, ok := e.source.Lookup(name)
+	return val, ok, e.source, nil
+}
+
+// Bool extracts bool value from variable named name
+// and returns defaultValue if it is absent or can not be parsed
+func (e *Env) Bool(name string, defaultValue bool, opts ...Option) bool {
+	res, _, err := e.boolValue([]string{name}, defaultValue, true, opts)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return res
+}
+
+// BoolStrict extracts bool value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) BoolStrict(name string, defaultValue bool, opts ...Option) (bool, error) {
+	res, _, err := e.boolValue([]string{name}, defaultValue, true, opts)
+	return res, err
+}
+
+func (e *Env) boolValue(names []string, defaultValue bool, hasDefault bool, opts []Option) (bool, bool, error) {
+	if !e.registered(names) {
+		names, opts := detach(names, opts)
+		e.register(names, func(env *Env) error {
+			_, _, err := env.boolValue(names, defaultValue, hasDefault, opts)
+			return err
+		})
+	}
+
+	var def defValue
+	if hasDefault {
+		def = boolDef(defaultValue)
+	}
+
+	o := newOptions(opts)
+	name, strVal, ok, err := e.value(names, def, o)
+	if err != nil {
+		return false, false, err
+	}
+	if !ok {
+		return defaultValue, false, nil
+	}
+
+	res, err := e.parseBool(strVal)
+	if err != nil {
+		return false, true, &ParseError{Var: name, Raw: strVal, Type: "bool", Err: err}
+	}
+
+	return res, true, nil
+}
+
+// Duration extracts time.Duration value from variable named name
+// and returns defaultValue if it is absent or can not be parsed
+func (e *Env) Duration(name string, defaultValue time.Duration, opts ...Option) time.Duration {
+	res, _, err := e.durationValue([]string{name}, defaultValue, true, opts)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return res
+}
+
+// DurationStrict extracts time.Duration value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) DurationStrict(name string, defaultValue time.Duration, opts ...Option) (time.Duration, error) {
+	res, _, err := e.durationValue([]string{name}, defaultValue, true, opts)
+	return res, err
+}
+
+func (e *Env) durationValue(names []string, defaultValue time.Duration, hasDefault bool, opts []Option) (time.Duration, bool, error) {
+	if !e.registered(names) {
+		names, opts := detach(names, opts)
+		e.register(names, func(env *Env) error {
+			_, _, err := env.durationValue(names, defaultValue, hasDefault, opts)
+			return err
+		})
+	}
+
+	var def defValue
+	if hasDefault {
+		def = durationDef(defaultValue)
+	}
+
+	o := newOptions(opts)
+	name, strVal, ok, err := e.value(names, def, o)
+	if err != nil {
+		return 0, false, err
+	}
+	if !ok {
+		return defaultValue, false, nil
+	}
+
+	d, err := e.parseDuration(strVal)
+	if err != nil {
+		return 0, true, &ParseError{Var: name, Raw: strVal, Type: "time.Duration", Err: err}
+	}
+
+	if c, ok := o.clampInt(int64(d)); ok {
+		d = time.Duration(c)
+	}
+
+	if err := o.checkIntRange(name, strVal, int64(d), formatDuration); err != nil {
+		return 0, true, err
+	}
+
+	return d, true, nil
+}
+
+// Float64 extracts float64 value from variable named name
+// and returns defaultValue if it is absent or can not be parsed
+func (e *Env) Float64(name string, defaultValue float64, opts ...Option) float64 {
+	res, _, err := e.float64Value([]string{name}, defaultValue, true, opts)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return res
+}
+
+// Float64Strict extracts float64 value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) Float64Strict(name string, defaultValue float64, opts ...Option) (float64, error) {
+	res, _, err := e.float64Value([]string{name}, defaultValue, true, opts)
+	return res, err
+}
+
+func (e *Env) float64Value(names []string, defaultValue float64, hasDefault bool, opts []Option) (float64, bool, error) {
+	if !e.registered(names) {
+		names, opts := detach(names, opts)
+		e.register(names, func(env *Env) error {
+			_, _, err := env.float64Value(names, defaultValue, hasDefault, opts)
+			return err
+		})
+	}
+
+	var def defValue
+	if hasDefault {
+		def = float64Def(defaultValue)
+	}
+
+	o := newOptions(opts)
+	name, strVal, ok, err := e.value(names, def, o)
+	if err != nil {
+		return 0, false, err
+	}
+	if !ok {
+		return defaultValue, false, nil
+	}
+
+	f, err := e.parseFloat(strVal)
+	if err != nil {
+		return 0, true, &ParseError{Var: name, Raw: strVal, Type: "float64", Err: err}
+	}
+
+	if c, ok := o.clamp(f); ok {
+		f = c
+	}
+
+	if err := o.checkRange(name, strVal, f, formatFloat); err != nil {
+		return 0, true, err
+	}
+
+	return f, true, nil
+}
+
+// Int extracts int value from variable named name
+// and returns defaultValue if it is absent or can not be parsed
+func (e *Env) Int(name string, defaultValue int, opts ...Option) int {
+	res, _, err := e.intValue([]string{name}, defaultValue, true, opts)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return res
+}
+
+// IntStrict extracts int value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) IntStrict(name string, defaultValue int, opts ...Option) (int, error) {
+	res, _, err := e.intValue([]string{name}, defaultValue, true, opts)
+	return res, err
+}
+
+func (e *Env) intValue(names []string, defaultValue int, hasDefault bool, opts []Option) (int, bool, error) {
+	if !e.registered(names) {
+		names, opts := detach(names, opts)
+		e.register(names, func(env *Env) error {
+			_, _, err := env.intValue(names, defaultValue, hasDefault, opts)
+			return err
+		})
+	}
+
+	var def defValue
+	if hasDefault {
+		def = intDef(defaultValue)
+	}
+
+	o := newOptions(opts)
+	name, strVal, ok, err := e.value(names, def, o)
+	if err != nil {
+		return 0, false, err
+	}
+	if !ok {
+		return defaultValue, false, nil
+	}
+
+	i64, err := e.parseInt("int", strVal, 0, &o)
+	if err != nil {
+		return 0, true, &ParseError{Var: name, Raw: strVal, Type: "int", Err: err}
+	}
+
+	if c, ok := o.clampInt(i64); ok {
+		i64 = c
+	}
+
+	if err := o.checkIntRange(name, strVal, i64, formatFloat); err != nil {
+		return 0, true, err
+	}
+
+	return int(i64), true, nil
+}
+
+// Int64 extracts int64 value from variable named name
+// and returns defaultValue if it is absent or can not be parsed
+func (e *Env) Int64(name string, defaultValue int64, opts ...Option) int64 {
+	res, _, err := e.int64Value([]string{name}, defaultValue, true, opts)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return res
+}
+
+// Int64Strict extracts int64 value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) Int64Strict(name string, defaultValue int64, opts ...Option) (int64, error) {
+	res, _, err := e.int64Value([]string{name}, defaultValue, true, opts)
+	return res, err
+}
+
+func (e *Env) int64Value(names []string, defaultValue int64, hasDefault bool, opts []Option) (int64, bool, error) {
+	if !e.registered(names) {
+		names, opts := detach(names, opts)
+		e.register(names, func(env *Env) error {
+			_, _, err := env.int64Value(names, defaultValue, hasDefault, opts)
+			return err
+		})
+	}
+
+	var def defValue
+	if hasDefault {
+		def = int64Def(defaultValue)
+	}
+
+	o := newOptions(opts)
+	name, strVal, ok, err := e.value(names, def, o)
+	if err != nil {
+		return 0, false, err
+	}
+	if !ok {
+		return defaultValue, false, nil
+	}
+
+	i64, err := e.parseInt("int64", strVal, 64, &o)
+	if err != nil {
+		return 0, true, &ParseError{Var: name, Raw: strVal, Type: "int64", Err: err}
+	}
+
+	if c, ok := o.clampInt(i64); ok {
+		i64 = c
+	}
+
+	if err := o.checkIntRange(name, strVal, i64, formatFloat); err != nil {
+		return 0, true, err
+	}
+
+	return i64, true, nil
+}
+
+// String extracts string value from variable named name
+// and returns defaultValue if it is absent
+func (e *Env) String(name, defaultValue string, opts ...Option) string {
+	if !e.registered([]string{name}) {
+		opts := append([]Option(nil), opts...)
+		e.register([]string{name}, func(env *Env) error {
+			_, _, _, err := env.value([]string{name}, stringDef(defaultValue), newOptions(opts))
+			return err
+		})
+	}
+
+	_, val, ok, err := e.value([]string{name}, stringDef(defaultValue), newOptions(opts))
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+	if !ok {
+		return defaultValue
+	}
+
+	return val
+}
+
+// NonEmptyString extracts string value from variable named name
+// and returns defaultValue if it is absent or set to an empty string
+func (e *Env) NonEmptyString(name, defaultValue string, opts ...Option) string {
+	val, err := e.NonEmptyStringStrict(name, defaultValue, opts...)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return val
+}
+
+// NonEmptyStringStrict extracts string value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// is set to an empty string, the method returns an error
+func (e *Env) NonEmptyStringStrict(name, defaultValue string, opts ...Option) (string, error) {
+	if !e.registered([]string{name}) {
+		opts := append([]Option(nil), opts...)
+		e.register([]string{name}, func(env *Env) error {
+			_, err := env.NonEmptyStringStrict(name, defaultValue, opts...)
+			return err
+		})
+	}
+
+	name, val, ok, err := e.value([]string{name}, stringDef(defaultValue), newOptions(opts))
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		if val == "" {
+			return "", &varError{name: name, code: CodeRequiredEmpty, msg: fmt.Sprintf("defenv: %s is set to an empty string", name)}
+		}
+
+		return val, nil
+	}
+
+	return defaultValue, nil
+}
+
+// Uint extracts uint value from variable named name
+// and returns defaultValue if it is absent or can not be parsed
+func (e *Env) Uint(name string, defaultValue uint, opts ...Option) uint {
+	res, _, err := e.uintValue([]string{name}, defaultValue, true, opts)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return res
+}
+
+// UintStrict extracts uint value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) UintStrict(name string, defaultValue uint, opts ...Option) (uint, error) {
+	res, _, err := e.uintValue([]string{name}, defaultValue, true, opts)
+	return res, err
+}
+
+func (e *Env) uintValue(names []string, defaultValue uint, hasDefault bool, opts []Option) (uint, bool, error) {
+	if !e.registered(names) {
+		names, opts := detach(names, opts)
+		e.register(names, func(env *Env) error {
+			_, _, err := env.uintValue(names, defaultValue, hasDefault, opts)
+			return err
+		})
+	}
+
+	var def defValue
+	if hasDefault {
+		def = uintDef(defaultValue)
+	}
+
+	o := newOptions(opts)
+	name, strVal, ok, err := e.value(names, def, o)
+	if err != nil {
+		return 0, false, err
+	}
+	if !ok {
+		return defaultValue, false, nil
+	}
+
+	u64, err := e.parseUint("uint", strVal, 0, &o)
+	if err != nil {
+		return 0, true, &ParseError{Var: name, Raw: strVal, Type: "uint", Err: err}
+	}
+
+	if c, ok := o.clampUint(u64); ok {
+		u64 = c
+	}
+
+	if err := o.checkUintRange(name, strVal, u64, formatFloat); err != nil {
+		return 0, true, err
+	}
+
+	return uint(u64), true, nil
+}
+
+// Uint64 extracts uint64 value from variable named name
+// and returns defaultValue if it is absent or can not be parsed
+func (e *Env) Uint64(name string, defaultValue uint64, opts ...Option) uint64 {
+	res, _, err := e.uint64Value([]string{name}, defaultValue, true, opts)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return res
+}
+
+// Uint64Strict extracts uint64 value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) Uint64Strict(name string, defaultValue uint64, opts ...Option) (uint64, error) {
+	res, _, err := e.uint64Value([]string{name}, defaultValue, true, opts)
+	return res, err
+}
+
+func (e *Env) uint64Value(names []string, defaultValue uint64, hasDefault bool, opts []Option) (uint64, bool, error) {
+	if !e.registered(names) {
+		names, opts := detach(names, opts)
+		e.register(names, func(env *Env) error {
+			_, _, err := env.uint64Value(names, defaultValue, hasDefault, opts)
+			return err
+		})
+	}
+
+	var def defValue
+	if hasDefault {
+		def = uint64Def(defaultValue)
+	}
+
+	o := newOptions(opts)
+	name, strVal, ok, err := e.value(names, def, o)
+	if err != nil {
+		return 0, false, err
+	}
+	if !ok {
+		return defaultValue, false, nil
+	}
+
+	u64, err := e.parseUint("uint64", strVal, 64, &o)
+	if err != nil {
+		return 0, true, &ParseError{Var: name, Raw: strVal, Type: "uint64", Err: err}
+	}
+
+	if c, ok := o.clampUint(u64); ok {
+		u64 = c
+	}
+
+	if err := o.checkUintRange(name, strVal, u64, formatFloat); err != nil {
+		return 0, true, err
+	}
+
+	return u64, true, nil
+}
@@ -0,0 +1,703 @@
+package defenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEnvSources(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		sources []Source
+		expRes  string
+	}{
+		{
+			name:    `value from the first source containing variable`,
+			sources: []Source{MapSource{}, MapSource{"VALUE": "second"}, MapSource{"VALUE": "third"}},
+			expRes:  "second",
+		},
+		{
+			name:    `empty value shadows later sources`,
+			sources: []Source{MapSource{"VALUE": ""}, MapSource{"VALUE": "second"}},
+			expRes:  "",
+		},
+		{
+			name:    `use default value then no source contains variable`,
+			sources: []Source{MapSource{"OTHER": "value"}},
+			expRes:  "default",
+		},
+		{
+			name:    `use default value then there are no sources`,
+			sources: nil,
+			expRes:  "default",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NewEnv(tc.sources...).String("VALUE", "default")
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	var names []string
+	env := New(func(name string) (string, bool) {
+		names = append(names, name)
+		if name == "PORT" {
+			return "8080", true
+		}
+		return "", false
+	})
+
+	if res := env.Int("PORT", 80); res != 8080 {
+		t.Errorf("expected value: %d, got: %d", 8080, res)
+	}
+	if res := env.String("HOST", "localhost"); res != "localhost" {
+		t.Errorf("expected value: %s, got: %s", "localhost", res)
+	}
+	if fmt.Sprint(names) != "[PORT HOST]" {
+		t.Errorf("expected lookups: [PORT HOST], got: %v", names)
+	}
+}
+
+func TestFromEnviron(t *testing.T) {
+	env := FromEnviron([]string{
+		"PORT=8080",
+		"DSN=user=admin host=db",
+		"EMPTY=",
+		"GARBAGE",
+		"DUP=first",
+		"DUP=last",
+	})
+
+	for _, tc := range []struct {
+		name   string
+		key    string
+		expRes string
+	}{
+		{
+			name:   `simple value`,
+			key:    "PORT",
+			expRes: "8080",
+		},
+		{
+			name:   `value containing =`,
+			key:    "DSN",
+			expRes: "user=admin host=db",
+		},
+		{
+			name:   `empty value`,
+			key:    "EMPTY",
+			expRes: "",
+		},
+		{
+			name:   `use default value then entry has no =`,
+			key:    "GARBAGE",
+			expRes: "default",
+		},
+		{
+			name:   `the last value of duplicated key`,
+			key:    "DUP",
+			expRes: "last",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := env.String(tc.key, "default")
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestEnvWithPrefix(t *testing.T) {
+	env := NewEnv(MapSource{
+		"HOST":              "localhost",
+		"REDIS_HOST":        "redis",
+		"CACHE_HOST":        "cache",
+		"CACHE_RO_HOST":     "replica",
+		"REDIS_ARGS":        "-v",
+		"REDIS_ARGS_APPEND": "-q",
+	})
+
+	for _, tc := range []struct {
+		name   string
+		env    *Env
+		expRes string
+	}{
+		{
+			name:   `without prefix`,
+			env:    env,
+			expRes: "localhost",
+		},
+		{
+			name:   `with prefix REDIS_`,
+			env:    env.WithPrefix("REDIS_"),
+			expRes: "redis",
+		},
+		{
+			name:   `with prefix CACHE_`,
+			env:    env.WithPrefix("CACHE_"),
+			expRes: "cache",
+		},
+		{
+			name:   `with nested prefixes`,
+			env:    env.WithPrefix("CACHE_").WithPrefix("RO_"),
+			expRes: "replica",
+		},
+		{
+			name:   `use default value then prefixed variable is absent`,
+			env:    env.WithPrefix("DB_"),
+			expRes: "default",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.env.String("HOST", "default")
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+
+	if res := env.WithPrefix("REDIS_").Command("ARGS", nil); fmt.Sprint(res) != "[-v -q]" {
+		t.Errorf("expected value: [-v -q], got: %v", res)
+	}
+}
+
+func TestEnvWithTrimSpace(t *testing.T) {
+	env := NewEnv(MapSource{
+		"PORT":    " 8080\n",
+		"DEBUG":   "true\r\n",
+		"NAME":    "\t name ",
+		"TIMEOUT": "\n",
+	}).WithTrimSpace()
+
+	if res, err := env.IntStrict("PORT", 80); err != nil || res != 8080 {
+		t.Errorf("expected value: %d, got: %d (%v)", 8080, res, err)
+	}
+	if res, err := env.BoolStrict("DEBUG", false); err != nil || !res {
+		t.Errorf("expected value: %t, got: %t (%v)", true, res, err)
+	}
+	if res := env.String("NAME", ""); res != "name" {
+		t.Errorf("expected value: %q, got: %q", "name", res)
+	}
+	if _, err := env.DurationStrict("TIMEOUT", time.Second); fmt.Sprint(err) != `defenv: parse TIMEOUT="" as time.Duration: time: invalid duration ""` {
+		t.Errorf("expected error: %s, got: %v", `defenv: parse TIMEOUT="" as time.Duration: time: invalid duration ""`, err)
+	}
+
+	if _, err := NewEnv(MapSource{"PORT": " 8080\n"}).IntStrict("PORT", 80); err == nil {
+		t.Errorf("expected error without trimming")
+	}
+}
+
+func TestEnvWithUnquote(t *testing.T) {
+	env := NewEnv(MapSource{
+		"DOUBLE":     `"8080"`,
+		"SINGLE":     `'8080'`,
+		"MISMATCHED": `"8080'`,
+		"INNER":      `say "hi"`,
+		"QUOTE":      `"`,
+		"EMPTY":      `""`,
+		"SPACED":     ` "8080" `,
+	})
+
+	for _, tc := range []struct {
+		name   string
+		env    *Env
+		key    string
+		expRes string
+	}{
+		{
+			name:   `double quotes are removed`,
+			env:    env.WithUnquote(),
+			key:    "DOUBLE",
+			expRes: "8080",
+		},
+		{
+			name:   `single quotes are removed`,
+			env:    env.WithUnquote(),
+			key:    "SINGLE",
+			expRes: "8080",
+		},
+		{
+			name:   `mismatched quotes are kept`,
+			env:    env.WithUnquote(),
+			key:    "MISMATCHED",
+			expRes: `"8080'`,
+		},
+		{
+			name:   `inner quotes are kept`,
+			env:    env.WithUnquote(),
+			key:    "INNER",
+			expRes: `say "hi"`,
+		},
+		{
+			name:   `single quote character is kept`,
+			env:    env.WithUnquote(),
+			key:    "QUOTE",
+			expRes: `"`,
+		},
+		{
+			name:   `empty quoted value`,
+			env:    env.WithUnquote(),
+			key:    "EMPTY",
+			expRes: "",
+		},
+		{
+			name:   `quotes are removed after trimming`,
+			env:    env.WithUnquote().WithTrimSpace(),
+			key:    "SPACED",
+			expRes: "8080",
+		},
+		{
+			name:   `quotes are kept without option`,
+			env:    env,
+			key:    "DOUBLE",
+			expRes: `"8080"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.env.String(tc.key, "default")
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestEnvWithEmptyAsUnset(t *testing.T) {
+	env := NewEnv(MapSource{"PORT": "", "SPACE": " ", "QUOTED": `""`})
+
+	for _, tc := range []struct {
+		name   string
+		env    *Env
+		key    string
+		expRes int
+		expErr error
+	}{
+		{
+			name:   `use default value then variable is empty`,
+			env:    env.WithEmptyAsUnset(),
+			key:    "PORT",
+			expRes: 80,
+		},
+		{
+			name:   `use default value then variable is empty after trimming`,
+			env:    env.WithEmptyAsUnset().WithTrimSpace(),
+			key:    "SPACE",
+			expRes: 80,
+		},
+		{
+			name:   `use default value then variable is empty after unquoting`,
+			env:    env.WithEmptyAsUnset().WithUnquote(),
+			key:    "QUOTED",
+			expRes: 80,
+		},
+		{
+			name:   `fail then variable is empty without option`,
+			env:    env,
+			key:    "PORT",
+			expErr: errors.New(`defenv: parse PORT="" as int: invalid syntax`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.env.IntStrict(tc.key, 80)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %d, got: %d", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestEnvDoesNotReadProcessEnvironment(t *testing.T) {
+	defer func() {
+		if err := os.Unsetenv("VALUE"); err != nil {
+			t.Errorf("coudn't unset VALUE: %s", err)
+		}
+	}()
+
+	if err := os.Setenv("VALUE", "42"); err != nil {
+		t.Fatal(err)
+	}
+
+	env := NewEnv(MapSource{"VALUE": "7"})
+	if res := env.Int("VALUE", 0); res != 7 {
+		t.Errorf("expected value: %d, got: %d", 7, res)
+	}
+	if res := NewEnv(OS).Int("VALUE", 0); res != 42 {
+		t.Errorf("expected value: %d, got: %d", 42, res)
+	}
+}
+
+func TestEnvExpander(t *testing.T) {
+	x := NewEnv(MapSource{"SCHEME": "https", "HOST": "example.com"}).Expander()
+	res := x.Expand("${SCHEME}://${HOST}")
+	if res != "https://example.com" {
+		t.Errorf("expected value: %s, got: %s", "https://example.com", res)
+	}
+}
+
+func TestEnvWithFileFallback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "defenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	secret := filepath.Join(dir, "secret")
+	if err := ioutil.WriteFile(secret, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	port := filepath.Join(dir, "port")
+	if err := ioutil.WriteFile(port, []byte("bad"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	absent := filepath.Join(dir, "absent")
+
+	for _, tc := range []struct {
+		name   string
+		src    MapSource
+		get    func(env *Env) (interface{}, error)
+		expRes interface{}
+		expErr error
+	}{
+		{
+			name: `value from file then variable is absent`,
+			src:  MapSource{"PASSWORD_FILE": secret},
+			get: func(env *Env) (interface{}, error) {
+				return env.String("PASSWORD", "default"), nil
+			},
+			expRes: "s3cr3t",
+		},
+		{
+			name: `variable takes precedence over file`,
+			src:  MapSource{"PASSWORD": "plain", "PASSWORD_FILE": secret},
+			get: func(env *Env) (interface{}, error) {
+				return env.String("PASSWORD", "default"), nil
+			},
+			expRes: "plain",
+		},
+		{
+			name: `use default value then neither variable nor file is set`,
+			src:  MapSource{},
+			get: func(env *Env) (interface{}, error) {
+				return env.String("PASSWORD", "default"), nil
+			},
+			expRes: "default",
+		},
+		{
+			name: `use default value then file can not be read`,
+			src:  MapSource{"PASSWORD_FILE": absent},
+			get: func(env *Env) (interface{}, error) {
+				return env.String("PASSWORD", "default"), nil
+			},
+			expRes: "default",
+		},
+		{
+			name: `fail then file can not be read`,
+			src:  MapSource{"PORT_FILE": absent},
+			get: func(env *Env) (interface{}, error) {
+				return env.IntStrict("PORT", 80)
+			},
+			expErr: fmt.Errorf("defenv: PORT is not readable from PORT_FILE: open %s: no such file or directory", absent),
+		},
+		{
+			name: `fail then file content can not be parsed`,
+			src:  MapSource{"PORT_FILE": port},
+			get: func(env *Env) (interface{}, error) {
+				return env.IntStrict("PORT", 80)
+			},
+			expErr: errors.New(`defenv: parse PORT="bad" as int: invalid syntax`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.get(NewEnv(tc.src).WithFileFallback())
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if tc.expErr == nil && res != tc.expRes {
+				t.Errorf("expected value: %v, got: %v", tc.expRes, res)
+			}
+		})
+	}
+
+	_, err = NewEnv(MapSource{"PORT_FILE": absent}).WithFileFallback().IntStrict("PORT", 80)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected error wrapping: %v, got: %v", os.ErrNotExist, err)
+	}
+	if code := ErrorCode(err); code != CodeFileError {
+		t.Errorf("expected code: %s, got: %s", CodeFileError, code)
+	}
+
+	if res := NewEnv(MapSource{"PASSWORD_FILE": secret}).String("PASSWORD", "default"); res != "default" {
+		t.Errorf("expected value without file fallback: %s, got: %s", "default", res)
+	}
+}
+
+func TestEnvWithContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	src := &ctxSource{MapSource: MapSource{"PORT": "8080"}}
+	env := NewEnv(src)
+
+	if res, err := env.WithContext(context.Background()).IntStrict("PORT", 80); err != nil || res != 8080 {
+		t.Errorf("expected value: %d, got: %d (%v)", 8080, res, err)
+	}
+	if _, err := env.WithContext(canceled).IntStrict("PORT", 80); err != context.Canceled {
+		t.Errorf("expected error: %v, got: %v", context.Canceled, err)
+	}
+	if res := env.WithContext(canceled).Int("PORT", 80); res != 80 {
+		t.Errorf("expected value: %d, got: %d", 80, res)
+	}
+	if res, err := env.IntStrict("PORT", 80); err != nil || res != 8080 {
+		t.Errorf("expected value without context: %d, got: %d (%v)", 8080, res, err)
+	}
+}
+
+func TestEnvStrict(t *testing.T) {
+	env := NewEnv(MapSource{"PORT": "bad", "HOST": "", "WORKERS": "4"}).Strict(true)
+
+	for _, tc := range []struct {
+		name     string
+		read     func()
+		expPanic error
+	}{
+		{
+			name: `use default value then variable is absent`,
+			read: func() { env.Int("ABSENT", 80) },
+		},
+		{
+			name: `use value then variable is correct`,
+			read: func() { env.Int("WORKERS", 8) },
+		},
+		{
+			name:     `panic then variable can not be parsed`,
+			read:     func() { env.Int("PORT", 80) },
+			expPanic: errors.New(`defenv: parse PORT="bad" as int: invalid syntax`),
+		},
+		{
+			name:     `panic then variable of list can not be parsed`,
+			read:     func() { env.DateList("PORT", nil) },
+			expPanic: errors.New(`defenv: parse PORT="bad" as []time.Time: parsing time "bad" as "2006-01-02": cannot parse "bad" as "2006"`),
+		},
+		{
+			name:     `panic then non-empty variable is empty`,
+			read:     func() { env.NonEmptyString("HOST", "localhost") },
+			expPanic: errors.New(`defenv: HOST is set to an empty string`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if fmt.Sprint(r) != fmt.Sprint(tc.expPanic) {
+					t.Errorf("expected panic: %v, got: %v", tc.expPanic, r)
+				}
+			}()
+
+			tc.read()
+		})
+	}
+
+	if res := env.Strict(false).Int("PORT", 80); res != 80 {
+		t.Errorf("expected value: %d, got: %d", 80, res)
+	}
+}
+
+func TestEnvWithErrorHandler(t *testing.T) {
+	var errs []error
+	env := NewEnv(MapSource{"PORT": "bad"}).WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	if res := env.Int("PORT", 80); res != 80 {
+		t.Errorf("expected value: %d, got: %d", 80, res)
+	}
+	if res := env.Int("ABSENT", 80); res != 80 {
+		t.Errorf("expected value: %d, got: %d", 80, res)
+	}
+
+	expErrs := []error{errors.New(`defenv: parse PORT="bad" as int: invalid syntax`)}
+	if fmt.Sprint(errs) != fmt.Sprint(expErrs) {
+		t.Errorf("expected errors: %v, got: %v", expErrs, errs)
+	}
+}
+
+func TestEnvExitOnError(t *testing.T) {
+	var (
+		buf  bytes.Buffer
+		code = -1
+	)
+
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	exit = func(c int) { code = c }
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+		exit = os.Exit
+	}()
+
+	NewEnv(MapSource{"PORT": "bad"}).ExitOnError().Int("PORT", 80)
+
+	if code != 2 {
+		t.Errorf("expected exit code: %d, got: %d", 2, code)
+	}
+	expLog := "defenv: parse PORT=\"bad\" as int: invalid syntax\n"
+	if buf.String() != expLog {
+		t.Errorf("expected log: %q, got: %q", expLog, buf.String())
+	}
+}
+
+func TestEnvOnRead(t *testing.T) {
+	type read struct {
+		name      string
+		raw       string
+		used      Source
+		defaulted bool
+	}
+
+	var (
+		reads     []read
+		defaults  = MapSource{"PORT": "8080", "HOST": "localhost"}
+		overrides = MapSource{"PORT": "9090"}
+	)
+
+	env := NewEnv(overrides, defaults).OnRead(func(name, raw string, used Source, defaulted bool) {
+		reads = append(reads, read{name: name, raw: raw, used: used, defaulted: defaulted})
+	})
+
+	env.Int("PORT", 80)
+	env.String("HOST", "")
+	env.Duration("TIMEOUT", time.Second)
+	env.WithPrefix("APP_").Bool("DEBUG", false)
+
+	expReads := []read{
+		{name: "PORT", raw: "9090", used: overrides},
+		{name: "HOST", raw: "localhost", used: defaults},
+		{name: "TIMEOUT", defaulted: true},
+		{name: "APP_DEBUG", defaulted: true},
+	}
+	if fmt.Sprint(reads) != fmt.Sprint(expReads) {
+		t.Errorf("expected reads: %v, got: %v", expReads, reads)
+	}
+}
+
+// TestEnvConcurrentUse shares one Env between goroutines,
+// run it with -race to check synchronization of the shared state
+func TestEnvConcurrentUse(t *testing.T) {
+	metrics := NewMetrics()
+	env := NewEnv(Cached(MapSource{"PORT": "80", "TIMEOUT": "5s", "CMD": "run -v"}, 0)).
+		WithMetrics(metrics).
+		WithCache()
+	env.Deprecated("OLD_PORT", "NEW_PORT", func(string, string) {})
+	env.OnConflict(func(string, interface{}, interface{}) {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watched := make(chan error)
+	go func() {
+		watched <- env.Watch(ctx, time.Millisecond, func([]Change) {})
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				env.Int("PORT", i)
+				env.WithPrefix("APP_").Duration("TIMEOUT", time.Second)
+				env.Command("CMD", nil)
+				env.Int("NEW_PORT", 0)
+				env.Dump(ioutil.Discard)
+				env.Snapshot()
+				env.Accessed()
+				env.ValidateAll()
+				env.ErrorOnUnknown("APP_")
+				metrics.WriteTo(ioutil.Discard)
+				if j%10 == 0 {
+					env.ResetCache()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	cancel()
+	<-watched
+}
+
+func TestEnvScalarAllocs(t *testing.T) {
+	env := NewEnv(MapSource{"INT": "8", "BOOL": "true", "DURATION": "5s", "STRING": "value"})
+	env.OnConflict(func(name string, first, second interface{}) {})
+
+	for _, tc := range []struct {
+		name string
+		read func()
+	}{
+		{name: "int", read: func() { env.Int("INT", 8080) }},
+		{name: "bool", read: func() { env.Bool("BOOL", false) }},
+		{name: "duration", read: func() { env.Duration("DURATION", time.Second) }},
+		{name: "string", read: func() { env.String("STRING", "default") }},
+		{name: "absent", read: func() { env.Int("ABSENT", 8080) }},
+		{name: "absent string", read: func() { env.String("ABSENT_STRING", "default") }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tc.read); allocs != 0 {
+				t.Errorf("expected no allocations, got: %g", allocs)
+			}
+		})
+	}
+}
+
+// BenchmarkEnvScalar reads variables with defaults typical for configuration.
+// Repeated reads do not allocate
+func BenchmarkEnvScalar(b *testing.B) {
+	env := NewEnv(MapSource{"INT": "8", "BOOL": "true", "DURATION": "5s", "STRING": "value"})
+
+	b.Run("Int", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			env.Int("INT", 8080)
+		}
+	})
+	b.Run("Bool", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			env.Bool("BOOL", false)
+		}
+	})
+	b.Run("Duration", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			env.Duration("DURATION", time.Second)
+		}
+	})
+	b.Run("String", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			env.String("STRING", "default")
+		}
+	})
+	b.Run("Absent", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			env.Int("ABSENT", 8080)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNotSet is returned by getters if a required variable is absent,
+// use errors.Is to check for it
+var ErrNotSet = errors.New("defenv: variable is not set")
+
+// ErrFrozen is returned by getters if a variable is read for the first time
+// after Freeze, use errors.Is to check for it
+var ErrFrozen = errors.New("defenv: configuration is frozen")
+
+// errNotFinite is the reason a value which must be a finite number
+// can not be parsed
+var errNotFinite = errors.New("not a finite number")
+
+// Code is a stable machine-readable cause of an error
+type Code string
+
+// Codes of errors returned by the package
+const (
+	CodeNotSet        Code = "NOT_SET"        // a required variable is absent
+	CodeParseError    Code = "PARSE_ERROR"    // a value can not be parsed
+	CodeOutOfRange    Code = "OUT_OF_RANGE"   // a value violates Min, Max or another bound
+	CodeRequiredEmpty Code = "REQUIRED_EMPTY" // a value which must not be empty is empty
+	CodeFrozen        Code = "FROZEN"         // a variable is read after Freeze
+	CodeUnknown       Code = "UNKNOWN"        // a variable is not known to the program
+	CodeFileError     Code = "FILE_ERROR"     // a file named by a NAME_FILE variable can not be read
+	CodeDuplicate     Code = "DUPLICATE"      // a list contains the same value twice
+)
+
+// ErrorCode returns code of the first error of the package found in err's chain
+// or an empty string if there is none
+func ErrorCode(err error) Code {
+	var c interface{ Code() Code }
+	if errors.As(err, &c) {
+		return c.Code()
+	}
+
+	return ""
+}
+
+// ParseError is returned by strict getters if a variable can not be parsed
+type ParseError struct {
+	Var  string // name of the variable
+	Raw  string // value of the variable
+	Type string // type the value was parsed as, e.g. "int" or "time.Duration"
+	Err  error  // the reason the parsing failed
+}
+
+// Error returns a message like
+// defenv: parse WORKER_NUMBER="abc" as int: invalid syntax
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("defenv: parse %s=%s as %s: %s", e.Var, quoteValue(e.Var, e.Raw), e.Type, e.reason())
+}
+
+// reason returns message of the reason the parsing failed
+// without the value of a sensitive variable
+func (e *ParseError) reason() string {
+	if ne, ok := e.Err.(*strconv.NumError); ok {
+		// the value is already in the message
+		return ne.Err.Error()
+	}
+
+	reason := e.Err.Error()
+	if e.Raw != "" && IsSensitive(e.Var) {
+		reason = strings.Replace(reason, e.Raw, redacted, -1)
+	}
+
+	return reason
+}
+
+// Unwrap returns the reason the parsing failed
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns CodeParseError
+func (e *ParseError) Code() Code {
+	return CodeParseError
+}
+
+// notSetError reports names of absent required variables
+// and a name of a present variable similar to them
+type notSetError struct {
+	names      []string
+	suggestion string
+}
+
+func (e *notSetError) Error() string {
+	msg := "defenv: variable " + strings.Join(e.names, " or ") + " is not set"
+	if e.suggestion != "" {
+		msg += ", did you mean " + e.suggestion + "?"
+	}
+
+	return msg
+}
+
+func (e *notSetError) Unwrap() error {
+	return ErrNotSet
+}
+
+func (e *notSetError) Code() Code {
+	return CodeNotSet
+}
+
+// frozenError reports a variable read for the first time after Freeze
+type frozenError struct {
+	name string
+}
+
+func (e *frozenError) Error() string {
+	return "defenv: variable " + e.name + " is read after configuration is frozen"
+}
+
+func (e *frozenError) Unwrap() error {
+	return ErrFrozen
+}
+
+func (e *frozenError) Code() Code {
+	return CodeFrozen
+}
+
+// varError is a validation error of a variable,
+// err is its cause if any
+type varError struct {
+	name string
+	code Code
+	msg  string
+	err  error
+}
+
+func (e *varError) Error() string {
+	return e.msg
+}
+
+func (e *varError) Unwrap() error {
+	return e.err
+}
+
+func (e *varError) Code() Code {
+	return e.code
+}
+
+// errorVar returns name of the variable err is about
+// or an empty string if it is unknown
+func errorVar(err error) string {
+	switch err := err.(type) {
+	case *ParseError:
+		return err.Var
+	case *varError:
+		return err.name
+	case *notSetError:
+		if len(err.names) > 0 {
+			return err.names[0]
+		}
+	case *frozenError:
+		return err.name
+	}
+
+	return ""
+}
@@ -0,0 +1,154 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	env := NewEnv(MapSource{"WORKER_NUMBER": "abc", "WINDOW": "bad", "CMD": `echo "a`})
+
+	for _, tc := range []struct {
+		name    string
+		get     func() error
+		expVar  string
+		expRaw  string
+		expType string
+	}{
+		{
+			name: "int getter",
+			get: func() error {
+				_, err := env.IntStrict("WORKER_NUMBER", 8)
+				return err
+			},
+			expVar:  "WORKER_NUMBER",
+			expRaw:  "abc",
+			expType: "int",
+		},
+		{
+			name: "prefixed getter",
+			get: func() error {
+				_, err := env.WithPrefix("WORKER_").Uint64Strict("NUMBER", 8)
+				return err
+			},
+			expVar:  "WORKER_NUMBER",
+			expRaw:  "abc",
+			expType: "uint64",
+		},
+		{
+			name: "getter with fallback",
+			get: func() error {
+				_, err := env.DurationStrict("TIMEOUT", 0, Fallback("WORKER_NUMBER"))
+				return err
+			},
+			expVar:  "WORKER_NUMBER",
+			expRaw:  "abc",
+			expType: "time.Duration",
+		},
+		{
+			name: "window getter",
+			get: func() error {
+				_, err := env.TimeWindowStrict("WINDOW", Window{})
+				return err
+			},
+			expVar:  "WINDOW",
+			expRaw:  "bad",
+			expType: "defenv.Window",
+		},
+		{
+			name: "command getter",
+			get: func() error {
+				_, err := env.CommandStrict("CMD", nil)
+				return err
+			},
+			expVar:  "CMD",
+			expRaw:  `echo "a`,
+			expType: "[]string",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var perr *ParseError
+			if err := tc.get(); !errors.As(err, &perr) {
+				t.Fatalf("expected ParseError, got: %v", err)
+			}
+			if perr.Var != tc.expVar || perr.Raw != tc.expRaw || perr.Type != tc.expType {
+				t.Errorf("expected %s=%q as %s, got: %s=%q as %s",
+					tc.expVar, tc.expRaw, tc.expType, perr.Var, perr.Raw, perr.Type)
+			}
+		})
+	}
+
+	_, err := env.IntStrict("WORKER_NUMBER", 8)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected error wrapping strconv.ErrSyntax, got: %v", err)
+	}
+	if _, ok := errors.Unwrap(err).(*strconv.NumError); !ok {
+		t.Errorf("expected unwrapped *strconv.NumError, got: %T", errors.Unwrap(err))
+	}
+	expErr := `defenv: parse WORKER_NUMBER="abc" as int: invalid syntax`
+	if err.Error() != expErr {
+		t.Errorf("expected error: %s, got: %v", expErr, err)
+	}
+}
+
+func TestErrNotSet(t *testing.T) {
+	env := NewEnv(MapSource{"PORT": "8080"}).WithPrefix("APP_")
+
+	_, err := env.IntStrict("PORT", 80, Required(), Fallback("HTTP_PORT"))
+	if !errors.Is(err, ErrNotSet) {
+		t.Errorf("expected ErrNotSet, got: %v", err)
+	}
+	expErr := "defenv: variable APP_PORT or APP_HTTP_PORT is not set"
+	if err == nil || err.Error() != expErr {
+		t.Errorf("expected error: %s, got: %v", expErr, err)
+	}
+
+	if res := env.Int("PORT", 80, Required()); res != 80 {
+		t.Errorf("expected value: %d, got: %d", 80, res)
+	}
+
+	_, err = env.Expander().ExpandStrict("$APP_HOST")
+	if !errors.Is(err, ErrNotSet) {
+		t.Errorf("expected ErrNotSet from ExpandStrict, got: %v", err)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	env := NewEnv(MapSource{"INT": "abc", "SMALL": "0", "EMPTY": "", "DATES": "2018-01-01,2018-01-01", "APP_X": "1"})
+
+	_, notSetErr := env.IntStrict("ABSENT", 0, Required())
+	_, parseErr := env.IntStrict("INT", 0)
+	_, rangeErr := env.IntStrict("SMALL", 1, Min(1))
+	_, emptyErr := env.NonEmptyStringStrict("EMPTY", "")
+	_, duplicateErr := env.DateListStrict("DATES", nil)
+	unknownErr := env.ErrorOnUnknown("APP_")
+	env.Freeze()
+	_, _, frozenErr := env.IntLookup("NEW")
+
+	tt := []struct {
+		name    string
+		err     error
+		expCode Code
+	}{
+		{name: "required variable is absent then NOT_SET", err: notSetErr, expCode: CodeNotSet},
+		{name: "value can not be parsed then PARSE_ERROR", err: parseErr, expCode: CodeParseError},
+		{name: "value is less than minimum then OUT_OF_RANGE", err: rangeErr, expCode: CodeOutOfRange},
+		{name: "value is empty then REQUIRED_EMPTY", err: emptyErr, expCode: CodeRequiredEmpty},
+		{name: "list has duplicates then DUPLICATE", err: duplicateErr, expCode: CodeDuplicate},
+		{name: "variable is unknown then UNKNOWN", err: unknownErr, expCode: CodeUnknown},
+		{name: "variable is read after freeze then FROZEN", err: frozenErr, expCode: CodeFrozen},
+		{name: "error is wrapped then code of the wrapped error", err: fmt.Errorf("config: %w", parseErr), expCode: CodeParseError},
+		{name: "error is foreign then empty code", err: errors.New("foreign"), expCode: ""},
+		{name: "no error then empty code", err: nil, expCode: ""},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := ErrorCode(tc.err); code != tc.expCode {
+				t.Errorf("expected code: %q, got: %q", tc.expCode, code)
+			}
+		})
+	}
+}
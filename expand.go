@@ -0,0 +1,124 @@
+package defenv
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Expander replaces $VAR and ${VAR} references in strings with values
+// of environment variables. "$$" is replaced with a single "$".
+// The zero value resolves references against the process environment
+type Expander struct {
+	lookup func(name string) (string, bool)
+}
+
+// Expand replaces $VAR and ${VAR} references in s. References to absent
+// variables are replaced with an empty string, the same way os.Expand does
+func (x Expander) Expand(s string) string {
+	res, _ := x.expand(s, false)
+	return res
+}
+
+// ExpandStrict replaces $VAR and ${VAR} references in s. If a referenced
+// variable is absent or a reference is malformed, the method returns an error
+func (x Expander) ExpandStrict(s string) (string, error) {
+	return x.expand(s, true)
+}
+
+// Mapping returns value of variable named name or an empty string if it is absent.
+// It can be passed to os.Expand
+func (x Expander) Mapping(name string) string {
+	val, _ := x.lookupEnv(name)
+	return val
+}
+
+func (x Expander) lookupEnv(name string) (string, bool) {
+	if x.lookup == nil {
+		return os.LookupEnv(name)
+	}
+
+	return x.lookup(name)
+}
+
+func (x Expander) expand(s string, strict bool) (string, error) {
+	if strings.IndexByte(s, '$') < 0 {
+		return s, nil
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			buf.WriteByte(s[i])
+			continue
+		}
+
+		var name string
+		switch next := s[i+1]; {
+		case next == '$':
+			buf.WriteByte('$')
+			i++
+			continue
+		case next == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				if strict {
+					return "", fmt.Errorf("defenv: unterminated reference in %q", s)
+				}
+				buf.WriteString(s[i:])
+				return buf.String(), nil
+			}
+			name = s[i+2 : i+2+end]
+			if !isName(name) {
+				if strict {
+					return "", fmt.Errorf("defenv: bad reference ${%s}", name)
+				}
+				buf.WriteString(s[i : i+3+end])
+				i += 2 + end
+				continue
+			}
+			i += 2 + end
+		case isNameStart(next):
+			j := i + 2
+			for j < len(s) && isNameChar(s[j]) {
+				j++
+			}
+			name = s[i+1 : j]
+			i = j - 1
+		default:
+			buf.WriteByte('$')
+			continue
+		}
+
+		val, ok := x.lookupEnv(name)
+		if !ok && strict {
+			return "", &notSetError{names: []string{name}}
+		}
+		buf.WriteString(val)
+	}
+
+	return buf.String(), nil
+}
+
+func isName(s string) bool {
+	if s == "" || !isNameStart(s[0]) {
+		return false
+	}
+
+	for i := 1; i < len(s); i++ {
+		if !isNameChar(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || '0' <= c && c <= '9'
+}
@@ -0,0 +1,136 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestExpanderExpand(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		env    map[string]string
+		s      string
+		expRes string
+	}{
+		{
+			name:   `references are replaced with environment values`,
+			env:    map[string]string{"SCHEME": "https", "HOST": "example.com"},
+			s:      "${SCHEME}://$HOST/path",
+			expRes: "https://example.com/path",
+		},
+		{
+			name:   `absent variables are replaced with empty string`,
+			env:    map[string]string{},
+			s:      "[${ABSENT}]",
+			expRes: "[]",
+		},
+		{
+			name:   `double dollar is replaced with single dollar`,
+			env:    map[string]string{"USER": "admin"},
+			s:      "pa$$word-$USER-$$USER",
+			expRes: "pa$word-admin-$USER",
+		},
+		{
+			name:   `malformed references are left as is`,
+			env:    map[string]string{},
+			s:      "cost: 5$ ${1X} ${OPEN",
+			expRes: "cost: 5$ ${1X} ${OPEN",
+		},
+		{
+			name:   `string without references is not changed`,
+			env:    map[string]string{},
+			s:      "plain",
+			expRes: "plain",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				for name := range tc.env {
+					if err := os.Unsetenv(name); err != nil {
+						t.Errorf("coudn't unset %s: %s", name, err)
+					}
+				}
+			}()
+
+			for name, value := range tc.env {
+				if err := os.Setenv(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var x Expander
+			res := x.Expand(tc.s)
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestExpanderExpandStrict(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		env    map[string]string
+		s      string
+		expRes string
+		expErr error
+	}{
+		{
+			name:   `references are replaced with environment values`,
+			env:    map[string]string{"SCHEME": "https", "HOST": "example.com"},
+			s:      "${SCHEME}://$HOST:$$",
+			expRes: "https://example.com:$",
+		},
+		{
+			name:   `variable set to empty string is resolved`,
+			env:    map[string]string{"EMPTY": ""},
+			s:      "[$EMPTY]",
+			expRes: "[]",
+		},
+		{
+			name:   `fail then variable is absent`,
+			env:    map[string]string{},
+			s:      "${ABSENT}",
+			expErr: errors.New(`defenv: variable ABSENT is not set`),
+		},
+		{
+			name:   `fail then reference is not terminated`,
+			env:    map[string]string{},
+			s:      "${OPEN",
+			expErr: errors.New(`defenv: unterminated reference in "${OPEN"`),
+		},
+		{
+			name:   `fail then reference has bad name`,
+			env:    map[string]string{},
+			s:      "${A-B}",
+			expErr: errors.New(`defenv: bad reference ${A-B}`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				for name := range tc.env {
+					if err := os.Unsetenv(name); err != nil {
+						t.Errorf("coudn't unset %s: %s", name, err)
+					}
+				}
+			}()
+
+			for name, value := range tc.env {
+				if err := os.Setenv(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var x Expander
+			res, err := x.ExpandStrict(tc.s)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
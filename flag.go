@@ -0,0 +1,49 @@
+package defenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FlagSource collects variables passed on the command line as
+// "--env KEY=VALUE", "--env=KEY=VALUE", "-e KEY=VALUE" or "-e=KEY=VALUE".
+// Other arguments are ignored, parsing stops at "--". Put the result
+// before OS to let operators override single variables:
+//
+// flags, err := defenv.FlagSource(os.Args[1:])
+// ...
+// env := defenv.NewEnv(flags, defenv.OS)
+func FlagSource(args []string) (MapSource, error) {
+	src := MapSource{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+
+		var kv string
+		switch {
+		case arg == "--env" || arg == "-e":
+			i++
+			if i == len(args) {
+				return nil, fmt.Errorf("defenv: flag %s needs an argument", arg)
+			}
+			kv = args[i]
+		case strings.HasPrefix(arg, "--env="):
+			kv = arg[len("--env="):]
+		case strings.HasPrefix(arg, "-e="):
+			kv = arg[len("-e="):]
+		default:
+			continue
+		}
+
+		eq := strings.IndexByte(kv, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("defenv: invalid variable %q, expected KEY=VALUE", kv)
+		}
+
+		src[kv[:eq]] = kv[eq+1:]
+	}
+
+	return src, nil
+}
@@ -0,0 +1,73 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFlagSource(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		args   []string
+		expRes MapSource
+		expErr error
+	}{
+		{
+			name: `all flag forms`,
+			args: []string{"--env", "A=1", "--env=B=2", "-e", "C=3", "-e=D=4=5"},
+			expRes: MapSource{
+				"A": "1",
+				"B": "2",
+				"C": "3",
+				"D": "4=5",
+			},
+		},
+		{
+			name:   `other arguments are ignored`,
+			args:   []string{"-v", "serve", "--port", "80", "-e", "A=1", "file.txt"},
+			expRes: MapSource{"A": "1"},
+		},
+		{
+			name:   `empty value`,
+			args:   []string{"-e", "A="},
+			expRes: MapSource{"A": ""},
+		},
+		{
+			name:   `later flag overrides earlier`,
+			args:   []string{"-e", "A=1", "-e", "A=2"},
+			expRes: MapSource{"A": "2"},
+		},
+		{
+			name:   `parsing stops at --`,
+			args:   []string{"-e", "A=1", "--", "-e", "B=2"},
+			expRes: MapSource{"A": "1"},
+		},
+		{
+			name:   `fail then flag has no argument`,
+			args:   []string{"serve", "--env"},
+			expErr: errors.New(`defenv: flag --env needs an argument`),
+		},
+		{
+			name:   `fail then argument has no =`,
+			args:   []string{"-e", "A"},
+			expErr: errors.New(`defenv: invalid variable "A", expected KEY=VALUE`),
+		},
+		{
+			name:   `fail then argument has empty name`,
+			args:   []string{"--env==1"},
+			expErr: errors.New(`defenv: invalid variable "=1", expected KEY=VALUE`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := FlagSource(tc.args)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if tc.expErr == nil && !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %q, got: %q", tc.expRes, res)
+			}
+		})
+	}
+}
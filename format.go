@@ -0,0 +1,109 @@
+package defenv
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// ANSI escape sequences used by FormatErrorColor
+const (
+	colorVar   = "\x1b[1;31m"
+	colorHint  = "\x1b[2m"
+	colorReset = "\x1b[0m"
+)
+
+// hints describe values expected by parse types
+var hints = map[string]string{
+	"bool":          "use true or false",
+	"int":           "use an integer, e.g. 8",
+	"int64":         "use an integer, e.g. 8",
+	"uint":          "use a non-negative integer, e.g. 8",
+	"uint64":        "use a non-negative integer, e.g. 8",
+	"float64":       "use a number, e.g. 0.5",
+	"time.Duration": `use a duration with a unit, e.g. "5s" or "1h30m"`,
+	"[]string":      "check quotes and escapes",
+	"[]time.Time":   "use comma-separated dates, e.g. 2006-01-02,2006-01-03",
+	"defenv.Window": "use a time window, e.g. 09:00-18:00",
+}
+
+// FormatError renders err, e.g. one returned by Collector or ValidateAll,
+// as an aligned list for terminal output of command line tools.
+// Every line contains a variable, the problem and a hint how to fix it
+func FormatError(err error) string {
+	return formatError(err, false)
+}
+
+// FormatErrorColor is like FormatError, but highlights
+// variables and hints with ANSI escape sequences
+func FormatErrorColor(err error) string {
+	return formatError(err, true)
+}
+
+func formatError(err error, color bool) string {
+	if err == nil {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	for _, line := range errorLines(err) {
+		name, hint := line[0], line[2]
+		if color {
+			name = colorVar + name + colorReset
+			if hint != "" {
+				hint = colorHint + hint + colorReset
+			}
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, line[1], hint)
+	}
+	tw.Flush()
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	for i, line := range lines {
+		if strings.HasSuffix(line, "\n") {
+			lines[i] = strings.TrimRight(line, " \n") + "\n"
+		}
+	}
+
+	return strings.Join(lines, "")
+}
+
+// errorLines splits err into variable, problem and hint triples
+func errorLines(err error) [][3]string {
+	switch err := err.(type) {
+	case multiError:
+		var lines [][3]string
+		for _, err := range err {
+			lines = append(lines, errorLines(err)...)
+		}
+		return lines
+	case *ParseError:
+		problem := fmt.Sprintf("can not parse %s as %s: %s", quoteValue(err.Var, err.Raw), err.Type, err.reason())
+		return [][3]string{{err.Var, problem, hints[err.Type]}}
+	case *notSetError:
+		var hint string
+		if err.suggestion != "" {
+			hint = "did you mean " + err.suggestion + "?"
+		}
+		return [][3]string{{strings.Join(err.names, " or "), "is not set", hint}}
+	case *varError:
+		problem := strings.TrimPrefix(err.msg, "defenv: "+err.name)
+		if strings.HasPrefix(problem, "=") {
+			problem = "value " + problem[1:]
+		}
+		return [][3]string{{err.name, strings.TrimSpace(problem), ""}}
+	case *frozenError:
+		return [][3]string{{err.name, "is read after configuration is frozen", "read it before Freeze"}}
+	case *unknownError:
+		lines := make([][3]string, len(err.names))
+		for i, name := range err.names {
+			lines[i] = [3]string{name, "is unknown", "check spelling or remove it"}
+		}
+		return lines
+	}
+
+	return [][3]string{{"-", strings.TrimPrefix(err.Error(), "defenv: "), ""}}
+}
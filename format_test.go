@@ -0,0 +1,35 @@
+package defenv
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFormatError(t *testing.T) {
+	c := NewEnv(MapSource{"PORT": "abc", "TIMEOUT": "5", "WORKERS": "0", "DB_PASWORD": "x"}).Collector()
+	c.Int("PORT", 8080)
+	c.Duration("TIMEOUT", time.Second)
+	c.Int("WORKERS", 8, Min(1))
+	c.String("DB_PASSWORD", "", Required())
+
+	err := c.Err()
+	exp := `PORT         can not parse "abc" as int: invalid syntax                              use an integer, e.g. 8
+TIMEOUT      can not parse "5" as time.Duration: time: missing unit in duration "5"  use a duration with a unit, e.g. "5s" or "1h30m"
+WORKERS      value "0" is less than minimum 1
+DB_PASSWORD  is not set                                                              did you mean DB_PASWORD?
+`
+	if res := FormatError(err); res != exp {
+		t.Errorf("expected result:\n%s\ngot:\n%s", exp, res)
+	}
+
+	expColor := "\x1b[1;31mPORT\x1b[0m  can not parse \"abc\" as int: invalid syntax  \x1b[2muse an integer, e.g. 8\x1b[0m\n" +
+		"\x1b[1;31m-\x1b[0m     failure\n"
+	if res := FormatErrorColor(multiError{err.(multiError)[0], errors.New("failure")}); res != expColor {
+		t.Errorf("expected result: %q, got: %q", expColor, res)
+	}
+
+	if res := FormatError(nil); res != "" {
+		t.Errorf("expected empty result, got: %q", res)
+	}
+}
@@ -0,0 +1,19 @@
+package defenv
+
+// Freeze forbids reads of environment variables not read before
+// through the package level functions, see Env.Freeze
+func Freeze() {
+	std.Freeze()
+}
+
+// Freeze forbids reads of variables not read before through the Env
+// or its copies, so all configuration is resolved at startup rather than
+// lazily in request paths. Variables read before Freeze can still be read.
+// Strict methods return an error wrapping ErrFrozen for new variables,
+// ordinary methods return the default value and report the error
+// the same way as a parsing error, so in strict mode they panic
+func (e *Env) Freeze() {
+	e.reads.mu.Lock()
+	e.reads.frozen = true
+	e.reads.mu.Unlock()
+}
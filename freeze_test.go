@@ -0,0 +1,46 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEnvFreeze(t *testing.T) {
+	env := NewEnv(MapSource{"PORT": "80", "HOST": "localhost"})
+	if port := env.Int("PORT", 0); port != 80 {
+		t.Fatalf("expected port: %d, got: %d", 80, port)
+	}
+
+	env.Freeze()
+
+	port, err := env.IntStrict("PORT", 0)
+	if err != nil || port != 80 {
+		t.Errorf("expected port: %d, got: %d (%v)", 80, port, err)
+	}
+
+	_, _, err = env.StringLookup("HOST")
+	expErr := errors.New("defenv: variable HOST is read after configuration is frozen")
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+	if !errors.Is(err, ErrFrozen) {
+		t.Errorf("expected error to wrap ErrFrozen, got: %v", err)
+	}
+
+	var failed error
+	handled := env.WithErrorHandler(func(err error) { failed = err })
+	if host := handled.String("HOST", "default"); host != "default" {
+		t.Errorf("expected host: %q, got: %q", "default", host)
+	}
+	if fmt.Sprint(failed) != fmt.Sprint(expErr) {
+		t.Errorf("expected handled error: %v, got: %v", expErr, failed)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic in strict mode")
+		}
+	}()
+	env.Strict(true).String("HOST", "")
+}
@@ -0,0 +1,134 @@
+package defenv
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// httpClient fetches documents of sources created without a client
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// HTTPSource is a Source serving variables from a document fetched
+// over HTTP(S). Documents with a JSON content type are parsed as with
+// JSONSource, others as dotenv. The document is fetched by NewHTTPSource
+// and by Refresh, lookups do not make requests. It is safe for concurrent use
+type HTTPSource struct {
+	client *http.Client
+	url    string
+	header http.Header
+
+	mu   sync.RWMutex
+	etag string
+	vars MapSource
+}
+
+// NewHTTPSource fetches the document at url and returns a Source serving it.
+// header is sent with every request, e.g. to pass an Authorization token.
+// Requests time out after 30 seconds, use NewHTTPSourceClient to change it
+func NewHTTPSource(url string, header http.Header) (*HTTPSource, error) {
+	return NewHTTPSourceContext(context.Background(), url, header)
+}
+
+// NewHTTPSourceContext is like NewHTTPSource, but the initial request is bound to ctx
+func NewHTTPSourceContext(ctx context.Context, url string, header http.Header) (*HTTPSource, error) {
+	return NewHTTPSourceClient(ctx, httpClient, url, header)
+}
+
+// NewHTTPSourceClient is like NewHTTPSourceContext, but the document is fetched
+// with client, e.g. to set a timeout or TLS configuration. A nil client
+// means the default one of NewHTTPSource
+func NewHTTPSourceClient(ctx context.Context, client *http.Client, url string, header http.Header) (*HTTPSource, error) {
+	if client == nil {
+		client = httpClient
+	}
+
+	s := &HTTPSource{client: client, url: url, header: header}
+	if err := s.RefreshContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Refresh fetches the document again. If the server supports ETag and
+// the document has not changed, it is not downloaded. On error the
+// previously fetched variables are kept
+func (s *HTTPSource) Refresh() error {
+	return s.RefreshContext(context.Background())
+}
+
+// RefreshContext is like Refresh, but the request is bound to ctx
+func (s *HTTPSource) RefreshContext(ctx context.Context) error {
+	req, err := http.NewRequest(http.MethodGet, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("defenv: %w", err)
+	}
+	req = req.WithContext(ctx)
+
+	for name, values := range s.header {
+		req.Header[name] = values
+	}
+
+	s.mu.RLock()
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+	s.mu.RUnlock()
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("defenv: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil
+	default:
+		return fmt.Errorf("defenv: GET %s: %s", s.url, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("defenv: GET %s: %w", s.url, err)
+	}
+
+	var vars MapSource
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		vars, err = parseJSON(data)
+	} else {
+		vars, err = parseDotEnv(string(data))
+	}
+	if err != nil {
+		return fmt.Errorf("defenv: GET %s: %w", s.url, err)
+	}
+
+	s.mu.Lock()
+	s.vars = vars
+	s.etag = resp.Header.Get("ETag")
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Lookup returns value of variable named name and reports whether it is present
+func (s *HTTPSource) Lookup(name string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.vars.Lookup(name)
+}
+
+// Names returns names of variables of the fetched document
+func (s *HTTPSource) Names() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.vars.Names()
+}
@@ -0,0 +1,117 @@
+package defenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPSource(t *testing.T) {
+	var (
+		contentType = "application/json"
+		body        = `{"PORT": 8080}`
+		etag        = `"v1"`
+		status      = http.StatusOK
+		requests    int
+		downloads   int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		downloads++
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	header := http.Header{"Authorization": {"Bearer token"}}
+
+	if _, err := NewHTTPSource(srv.URL, nil); fmt.Sprint(err) != fmt.Sprintf("defenv: GET %s: 401 Unauthorized", srv.URL) {
+		t.Errorf("unexpected error without authorization: %v", err)
+	}
+
+	src, err := NewHTTPSource(srv.URL, header)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	check := func(name, expRes string, expOk bool) {
+		res, ok := src.Lookup(name)
+		if ok != expOk || res != expRes {
+			t.Errorf("expected %s=%q (%t), got: %q (%t)", name, expRes, expOk, res, ok)
+		}
+	}
+
+	check("PORT", "8080", true)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := src.RefreshContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error wrapping: %v, got: %v", context.Canceled, err)
+	}
+
+	// not modified document is not downloaded again
+	if err := src.Refresh(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if downloads != 1 || requests != 3 {
+		t.Errorf("expected 3 requests and 1 download, got: %d requests and %d downloads", requests, downloads)
+	}
+	check("PORT", "8080", true)
+
+	// changed document in dotenv format
+	contentType, body, etag = "text/plain", "PORT=9090\nHOST=localhost\n", `"v2"`
+	if err := src.Refresh(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	check("PORT", "9090", true)
+	check("HOST", "localhost", true)
+
+	// failures keep previous variables
+	status = http.StatusInternalServerError
+	if err := src.Refresh(); fmt.Sprint(err) != fmt.Sprintf("defenv: GET %s: 500 Internal Server Error", srv.URL) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	check("PORT", "9090", true)
+
+	status, body, etag = http.StatusOK, "BAD\n", `"v3"`
+	if err := src.Refresh(); fmt.Sprint(err) != fmt.Sprintf("defenv: GET %s: line 1: missing =", srv.URL) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	check("PORT", "9090", true)
+}
+
+func TestHTTPSourceClient(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	client := &http.Client{Timeout: 10 * time.Millisecond}
+	_, err := NewHTTPSourceClient(context.Background(), client, srv.URL, nil)
+	var nerr net.Error
+	if !errors.As(err, &nerr) || !nerr.Timeout() {
+		t.Errorf("expected timeout error, got: %v", err)
+	}
+}
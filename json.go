@@ -0,0 +1,178 @@
+package defenv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// JSONSource parses a flat JSON object and returns its keys as variables.
+// String values are used as is, numbers and booleans are converted to
+// their JSON text. This is the format of key/value secrets in AWS Secrets
+// Manager and similar stores:
+//
+// out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String("prod/app")})
+// ...
+// src, err := defenv.JSONSource([]byte(*out.SecretString))
+func JSONSource(data []byte) (MapSource, error) {
+	src, err := parseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("defenv: %s", err)
+	}
+
+	return src, nil
+}
+
+// JSONSecretSource is a Source serving keys of a flat JSON object fetched
+// by a function, e.g. a named secret of AWS Secrets Manager, and fetching
+// it again once it is older than a TTL. It is safe for concurrent use
+type JSONSecretSource struct {
+	fetch func(ctx context.Context) ([]byte, error)
+	ttl   time.Duration
+	now   func() time.Time
+
+	refreshing sync.Mutex // serializes fetches
+
+	mu      sync.RWMutex
+	vars    MapSource
+	expires time.Time
+	onError func(error)
+}
+
+// NewJSONSecretSource fetches the object with fetch and returns a Source
+// serving it. A lookup made when the object is older than ttl fetches it
+// again, if ttl is not positive the object is fetched again only by Refresh.
+// Concurrent lookups share a single fetch. If fetching fails, the previously
+// fetched variables are kept and the error is passed to the hook set by
+// OnRefreshError. fetch usually calls GetSecretValue of a Secrets Manager client:
+//
+// src, err := defenv.NewJSONSecretSource(fetchSecret, 5*time.Minute)
+func NewJSONSecretSource(fetch func(ctx context.Context) ([]byte, error), ttl time.Duration) (*JSONSecretSource, error) {
+	s := &JSONSecretSource{fetch: fetch, ttl: ttl, now: time.Now}
+	if err := s.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Refresh fetches the object again. On error the previously
+// fetched variables are kept
+func (s *JSONSecretSource) Refresh(ctx context.Context) error {
+	s.refreshing.Lock()
+	defer s.refreshing.Unlock()
+
+	return s.refresh(ctx)
+}
+
+// OnRefreshError sets a hook called with errors of fetches made by lookups
+// once the object is older than the TTL, e.g. to log them. The previously
+// fetched variables are used until the next attempt after the TTL
+func (s *JSONSecretSource) OnRefreshError(hook func(err error)) {
+	s.mu.Lock()
+	s.onError = hook
+	s.mu.Unlock()
+}
+
+// refresh fetches the object, the caller must hold s.refreshing
+func (s *JSONSecretSource) refresh(ctx context.Context) error {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("defenv: fetch JSON secret: %w", err)
+	}
+
+	vars, err := parseJSON(data)
+	if err != nil {
+		return fmt.Errorf("defenv: %w", err)
+	}
+
+	s.mu.Lock()
+	s.vars = vars
+	s.expires = s.now().Add(s.ttl)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Lookup returns value of variable named name and reports whether it is present
+func (s *JSONSecretSource) Lookup(name string) (string, bool) {
+	val, ok, _ := s.LookupContext(context.Background(), name)
+	return val, ok
+}
+
+// LookupContext is like Lookup, but the object is fetched with ctx if it is
+// older than the TTL. Failed fetches are reported to the OnRefreshError hook,
+// not to the caller, the previously fetched variables are used until
+// the next attempt after the TTL
+func (s *JSONSecretSource) LookupContext(ctx context.Context, name string) (string, bool, error) {
+	if s.stale() {
+		s.refreshing.Lock()
+		// another lookup may have refreshed the object while we waited
+		if s.stale() {
+			if err := s.refresh(ctx); err != nil {
+				// retry after ttl rather than on every lookup
+				s.mu.Lock()
+				s.expires = s.now().Add(s.ttl)
+				hook := s.onError
+				s.mu.Unlock()
+
+				if hook != nil {
+					hook(err)
+				}
+			}
+		}
+		s.refreshing.Unlock()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.vars.Lookup(name)
+	return val, ok, nil
+}
+
+// stale reports whether the object is older than the TTL
+func (s *JSONSecretSource) stale() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.ttl > 0 && !s.now().Before(s.expires)
+}
+
+// Names returns names of variables of the fetched object
+func (s *JSONSecretSource) Names() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.vars.Names()
+}
+
+func parseJSON(data []byte) (MapSource, error) {
+	var obj map[string]interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	src := make(MapSource, len(obj))
+	for name, val := range obj {
+		switch v := val.(type) {
+		case string:
+			src[name] = v
+		case json.Number:
+			src[name] = v.String()
+		case bool:
+			src[name] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("value of %s is not a string, number or boolean", name)
+		}
+	}
+
+	return src, nil
+}
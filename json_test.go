@@ -0,0 +1,148 @@
+package defenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJSONSource(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		data   string
+		expRes MapSource
+		expErr error
+	}{
+		{
+			name: `strings, numbers and booleans`,
+			data: `{"username": "admin", "port": 5432, "ratio": 0.25, "tls": true}`,
+			expRes: MapSource{
+				"username": "admin",
+				"port":     "5432",
+				"ratio":    "0.25",
+				"tls":      "true",
+			},
+		},
+		{
+			name:   `empty object`,
+			data:   `{}`,
+			expRes: MapSource{},
+		},
+		{
+			name:   `fail then value is an object`,
+			data:   `{"db": {"port": 5432}}`,
+			expErr: errors.New(`defenv: value of db is not a string, number or boolean`),
+		},
+		{
+			name:   `fail then value is null`,
+			data:   `{"password": null}`,
+			expErr: errors.New(`defenv: value of password is not a string, number or boolean`),
+		},
+		{
+			name:   `fail then data is not an object`,
+			data:   `["a"]`,
+			expErr: errors.New(`defenv: json: cannot unmarshal array into Go value of type map[string]interface {}`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := JSONSource([]byte(tc.data))
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if tc.expErr == nil && !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %q, got: %q", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestJSONSecretSource(t *testing.T) {
+	var (
+		data    = `{"PASSWORD": "old"}`
+		fetches int
+		fail    error
+	)
+	fetch := func(ctx context.Context) ([]byte, error) {
+		fetches++
+		if fail != nil {
+			return nil, fail
+		}
+		return []byte(data), nil
+	}
+
+	src, err := NewJSONSecretSource(fetch, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	src.now = func() time.Time { return now }
+	var refreshErr error
+	src.OnRefreshError(func(err error) { refreshErr = err })
+
+	check := func(expRes string, expFetches int) {
+		t.Helper()
+		if res, _ := src.Lookup("PASSWORD"); res != expRes || fetches != expFetches {
+			t.Errorf("expected %q after %d fetches, got: %q after %d fetches", expRes, expFetches, res, fetches)
+		}
+	}
+
+	check("old", 1)
+
+	data = `{"PASSWORD": "new"}`
+	check("old", 1)
+
+	now = now.Add(2 * time.Minute)
+	check("new", 2)
+	check("new", 2)
+
+	fail = errors.New("throttled")
+	now = now.Add(2 * time.Minute)
+	check("new", 3)
+	check("new", 3)
+
+	expErr := errors.New("defenv: fetch JSON secret: throttled")
+	if fmt.Sprint(refreshErr) != fmt.Sprint(expErr) {
+		t.Errorf("expected refresh error: %v, got: %v", expErr, refreshErr)
+	}
+	if err := src.Refresh(context.Background()); fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+	if _, err := NewJSONSecretSource(fetch, 0); fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+}
+
+func TestJSONSecretSourceConcurrentRefresh(t *testing.T) {
+	var fetches int32
+	src, err := NewJSONSecretSource(func(ctx context.Context) ([]byte, error) {
+		atomic.AddInt32(&fetches, 1)
+		time.Sleep(10 * time.Millisecond)
+		return []byte(`{"PASSWORD": "s3cr3t"}`), nil
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now().Add(2 * time.Minute)
+	src.now = func() time.Time { return now }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res, ok := src.Lookup("PASSWORD"); !ok || res != "s3cr3t" {
+				t.Errorf("expected value: %s, got: %s (%t)", "s3cr3t", res, ok)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Errorf("expected 2 fetches, got: %d", n)
+	}
+}
@@ -0,0 +1,51 @@
+package defenv
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// KVDirSource is a Source reading variables from a directory where
+// every file is a variable: the file name is the variable name and
+// the file content is its value. This is how Kubernetes projects
+// ConfigMap and Secret volumes into a pod.
+//
+// Files are read on every lookup, so updates of the volume are picked up
+// immediately. Kubernetes updates volumes by atomically swapping the ..data
+// symlink, which means a lookup always sees either the old or the new
+// content. Hidden files such as ..data are not exposed as variables.
+// Values are not trimmed
+type KVDirSource string
+
+// Lookup returns content of the file named name and reports whether it can be read
+func (d KVDirSource) Lookup(name string) (string, bool) {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(string(d), name))
+	if err != nil {
+		return "", false
+	}
+
+	return string(data), true
+}
+
+// Names returns names of regular files of the directory, hidden files are skipped
+func (d KVDirSource) Names() ([]string, error) {
+	files, err := ioutil.ReadDir(string(d))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), ".") || file.IsDir() {
+			continue
+		}
+		names = append(names, file.Name())
+	}
+
+	return names, nil
+}
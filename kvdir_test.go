@@ -0,0 +1,122 @@
+package defenv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestKVDirSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "defenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// mimic the layout of a Kubernetes volume:
+	// KEY -> ..data/KEY, ..data -> ..2018_01_01
+	version := filepath.Join(dir, "..2018_01_01")
+	if err := os.Mkdir(version, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(version, "PORT"), []byte("8080"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(version, "CERT"), []byte("line1\nline2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("..2018_01_01", filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"PORT", "CERT"} {
+		if err := os.Symlink(filepath.Join("..data", name), filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "SUBDIR"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	src := KVDirSource(dir)
+
+	for _, tc := range []struct {
+		name   string
+		key    string
+		expRes string
+		expOk  bool
+	}{
+		{
+			name:   `value from file`,
+			key:    "PORT",
+			expRes: "8080",
+			expOk:  true,
+		},
+		{
+			name:   `value is not trimmed`,
+			key:    "CERT",
+			expRes: "line1\nline2\n",
+			expOk:  true,
+		},
+		{
+			name:  `absent then file does not exist`,
+			key:   "ABSENT",
+			expOk: false,
+		},
+		{
+			name:  `absent then file is a directory`,
+			key:   "SUBDIR",
+			expOk: false,
+		},
+		{
+			name:  `absent then name is hidden`,
+			key:   "..data",
+			expOk: false,
+		},
+		{
+			name:  `absent then name contains path separator`,
+			key:   "..data/PORT",
+			expOk: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, ok := src.Lookup(tc.key)
+			if ok != tc.expOk {
+				t.Errorf("expected presence: %t, got: %t", tc.expOk, ok)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %q, got: %q", tc.expRes, res)
+			}
+		})
+	}
+
+	names, err := src.Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(names)
+	if exp := []string{"CERT", "PORT"}; !reflect.DeepEqual(names, exp) {
+		t.Errorf("expected names: %q, got: %q", exp, names)
+	}
+
+	// atomic update: write new version and swap the ..data symlink
+	next := filepath.Join(dir, "..2018_01_02")
+	if err := os.Mkdir(next, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(next, "PORT"), []byte("9090"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("..2018_01_02", filepath.Join(dir, "..data_tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+
+	if res, _ := src.Lookup("PORT"); res != "9090" {
+		t.Errorf("expected value after update: %q, got: %q", "9090", res)
+	}
+}
@@ -0,0 +1,11 @@
+package defenv
+
+// List variables may have companions named with these suffixes.
+// Values of name_PREPEND and name_APPEND are parsed the same way as
+// the variable itself and merged with its value (or with the default value
+// if the variable is absent), so several configuration layers can
+// contribute to one list without overriding each other
+const (
+	prependSuffix = "_PREPEND"
+	appendSuffix  = "_APPEND"
+)
@@ -0,0 +1,109 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListCompanions(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		env    map[string]string
+		get    func() (interface{}, error)
+		expRes interface{}
+		expErr error
+	}{
+		{
+			name: `command with prepended and appended arguments`,
+			env: map[string]string{
+				"VALUE":         "ffmpeg -i in.mp4",
+				"VALUE_PREPEND": "nice -n 10",
+				"VALUE_APPEND":  "-y out.mp4",
+			},
+			get: func() (interface{}, error) {
+				return CommandStrict("VALUE", nil)
+			},
+			expRes: []string{"nice", "-n", "10", "ffmpeg", "-i", "in.mp4", "-y", "out.mp4"},
+		},
+		{
+			name: `appended arguments are merged with default value`,
+			env:  map[string]string{"VALUE_APPEND": "-v"},
+			get: func() (interface{}, error) {
+				return CommandStrict("VALUE", []string{"run"})
+			},
+			expRes: []string{"run", "-v"},
+		},
+		{
+			name: `fail then prepended arguments can not be parsed`,
+			env:  map[string]string{"VALUE": "run", "VALUE_PREPEND": "'bad"},
+			get: func() (interface{}, error) {
+				return CommandStrict("VALUE", nil)
+			},
+			expErr: errors.New(`defenv: parse VALUE_PREPEND="'bad" as []string: unterminated single quote`),
+		},
+		{
+			name: `use default value then appended arguments can not be parsed`,
+			env:  map[string]string{"VALUE": "run", "VALUE_APPEND": `"bad`},
+			get: func() (interface{}, error) {
+				return Command("VALUE", []string{"default"}), nil
+			},
+			expRes: []string{"default"},
+		},
+		{
+			name: `dates from companions are merged and sorted`,
+			env: map[string]string{
+				"VALUE":         "2018-05-01",
+				"VALUE_PREPEND": "2018-12-31",
+				"VALUE_APPEND":  "2018-01-01",
+			},
+			get: func() (interface{}, error) {
+				return DateListStrict("VALUE", nil)
+			},
+			expRes: []time.Time{date(2018, 1, 1), date(2018, 5, 1), date(2018, 12, 31)},
+		},
+		{
+			name: `appended dates are merged with default value`,
+			env:  map[string]string{"VALUE_APPEND": "2018-01-01"},
+			get: func() (interface{}, error) {
+				return DateListStrict("VALUE", []time.Time{date(2018, 3, 8)})
+			},
+			expRes: []time.Time{date(2018, 1, 1), date(2018, 3, 8)},
+		},
+		{
+			name: `fail then companion duplicates a date`,
+			env:  map[string]string{"VALUE": "2018-01-01", "VALUE_APPEND": "2018-01-01"},
+			get: func() (interface{}, error) {
+				return DateListStrict("VALUE", nil)
+			},
+			expErr: errors.New(`defenv: VALUE_APPEND="2018-01-01" contains duplicate date 2018-01-01`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				for name := range tc.env {
+					if err := os.Unsetenv(name); err != nil {
+						t.Errorf("coudn't unset %s: %s", name, err)
+					}
+				}
+			}()
+
+			for name, value := range tc.env {
+				if err := os.Setenv(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res, err := tc.get()
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if tc.expErr == nil && !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %v, got: %v", tc.expRes, res)
+			}
+		})
+	}
+}
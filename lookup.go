@@ -0,0 +1,122 @@
+package defenv
+
+import "time"
+
+// BoolLookup extracts bool value from environment variable named name
+// and reports whether it is present. If the environment variable
+// can not be parsed, the method returns an error
+func BoolLookup(name string, opts ...Option) (bool, bool, error) {
+	return std.BoolLookup(name, opts...)
+}
+
+// DurationLookup extracts time.Duration value from environment variable named name
+// and reports whether it is present. If the environment variable
+// can not be parsed, the method returns an error
+func DurationLookup(name string, opts ...Option) (time.Duration, bool, error) {
+	return std.DurationLookup(name, opts...)
+}
+
+// Float64Lookup extracts float64 value from environment variable named name
+// and reports whether it is present. If the environment variable
+// can not be parsed, the method returns an error
+func Float64Lookup(name string, opts ...Option) (float64, bool, error) {
+	return std.Float64Lookup(name, opts...)
+}
+
+// IntLookup extracts int value from environment variable named name
+// and reports whether it is present. If the environment variable
+// can not be parsed, the method returns an error
+func IntLookup(name string, opts ...Option) (int, bool, error) {
+	return std.IntLookup(name, opts...)
+}
+
+// Int64Lookup extracts int64 value from environment variable named name
+// and reports whether it is present. If the environment variable
+// can not be parsed, the method returns an error
+func Int64Lookup(name string, opts ...Option) (int64, bool, error) {
+	return std.Int64Lookup(name, opts...)
+}
+
+// StringLookup extracts string value from environment variable named name
+// and reports whether it is present
+func StringLookup(name string, opts ...Option) (string, bool, error) {
+	return std.StringLookup(name, opts...)
+}
+
+// UintLookup extracts uint value from environment variable named name
+// and reports whether it is present. If the environment variable
+// can not be parsed, the method returns an error
+func UintLookup(name string, opts ...Option) (uint, bool, error) {
+	return std.UintLookup(name, opts...)
+}
+
+// Uint64Lookup extracts uint64 value from environment variable named name
+// and reports whether it is present. If the environment variable
+// can not be parsed, the method returns an error
+func Uint64Lookup(name string, opts ...Option) (uint64, bool, error) {
+	return std.Uint64Lookup(name, opts...)
+}
+
+// BoolLookup extracts bool value from variable named name
+// and reports whether it is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) BoolLookup(name string, opts ...Option) (bool, bool, error) {
+	return e.boolValue([]string{name}, false, false, opts)
+}
+
+// DurationLookup extracts time.Duration value from variable named name
+// and reports whether it is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) DurationLookup(name string, opts ...Option) (time.Duration, bool, error) {
+	return e.durationValue([]string{name}, 0, false, opts)
+}
+
+// Float64Lookup extracts float64 value from variable named name
+// and reports whether it is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) Float64Lookup(name string, opts ...Option) (float64, bool, error) {
+	return e.float64Value([]string{name}, 0, false, opts)
+}
+
+// IntLookup extracts int value from variable named name
+// and reports whether it is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) IntLookup(name string, opts ...Option) (int, bool, error) {
+	return e.intValue([]string{name}, 0, false, opts)
+}
+
+// Int64Lookup extracts int64 value from variable named name
+// and reports whether it is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) Int64Lookup(name string, opts ...Option) (int64, bool, error) {
+	return e.int64Value([]string{name}, 0, false, opts)
+}
+
+// StringLookup extracts string value from variable named name
+// and reports whether it is present
+func (e *Env) StringLookup(name string, opts ...Option) (string, bool, error) {
+	if !e.registered([]string{name}) {
+		opts := append([]Option(nil), opts...)
+		e.register([]string{name}, func(env *Env) error {
+			_, _, err := env.StringLookup(name, opts...)
+			return err
+		})
+	}
+
+	_, val, ok, err := e.value([]string{name}, defValue{}, newOptions(opts))
+	return val, ok, err
+}
+
+// UintLookup extracts uint value from variable named name
+// and reports whether it is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) UintLookup(name string, opts ...Option) (uint, bool, error) {
+	return e.uintValue([]string{name}, 0, false, opts)
+}
+
+// Uint64Lookup extracts uint64 value from variable named name
+// and reports whether it is present. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) Uint64Lookup(name string, opts ...Option) (uint64, bool, error) {
+	return e.uint64Value([]string{name}, 0, false, opts)
+}
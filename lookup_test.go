@@ -0,0 +1,128 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIntLookup(t *testing.T) {
+	testCases := []struct {
+		name       string
+		source     MapSource
+		expRes     int
+		expPresent bool
+		expErr     error
+	}{
+		{
+			name:       "success then environment value is correct",
+			source:     MapSource{"WORKER_NUMBER": "16"},
+			expRes:     16,
+			expPresent: true,
+		},
+		{
+			name:       "success then environment is absent",
+			source:     MapSource{},
+			expRes:     0,
+			expPresent: false,
+		},
+		{
+			name:       "fail then environment is bad",
+			source:     MapSource{"WORKER_NUMBER": "bad"},
+			expPresent: true,
+			expErr:     errors.New(`defenv: parse WORKER_NUMBER="bad" as int: invalid syntax`),
+		},
+		{
+			name:       "fail then environment is out of range",
+			source:     MapSource{"WORKER_NUMBER": "100"},
+			expPresent: true,
+			expErr:     errors.New(`defenv: WORKER_NUMBER="100" is greater than maximum 64`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, present, err := NewEnv(tc.source).IntLookup("WORKER_NUMBER", Max(64))
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if present != tc.expPresent {
+				t.Errorf("expected presence: %t, got: %t", tc.expPresent, present)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected result: %d, got: %d", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestDurationLookup(t *testing.T) {
+	env := NewEnv(MapSource{"TIMEOUT": "3s"})
+
+	res, present, err := env.DurationLookup("TIMEOUT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !present || res != 3*time.Second {
+		t.Errorf("expected result: %s (true), got: %s (%t)", 3*time.Second, res, present)
+	}
+}
+
+func TestStringLookup(t *testing.T) {
+	env := NewEnv(MapSource{"EMPTY": ""})
+
+	res, present, err := env.StringLookup("EMPTY")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !present || res != "" {
+		t.Errorf("expected result: %q (true), got: %q (%t)", "", res, present)
+	}
+
+	if _, present, _ = env.StringLookup("EMPTY", EmptyAsUnset()); present {
+		t.Error("expected empty value to be absent with EmptyAsUnset")
+	}
+
+	if _, present, _ = env.StringLookup("ABSENT"); present {
+		t.Error("expected absent value")
+	}
+}
+
+func TestLookupWithoutDefault(t *testing.T) {
+	var conflicts int
+	env := NewEnv(MapSource{})
+	env.OnConflict(func(name string, first, second interface{}) { conflicts++ })
+
+	env.Int("PORT", 8080)
+	if _, present, _ := env.IntLookup("PORT"); present {
+		t.Error("expected absent value")
+	}
+	env.BoolLookup("DEBUG")
+
+	if conflicts != 0 {
+		t.Errorf("expected no conflicts, got: %d", conflicts)
+	}
+	if entry := env.reads.entry("PORT"); entry.def.value() != 8080 {
+		t.Errorf("expected default value: %d, got: %v", 8080, entry.def.value())
+	}
+	if entry := env.reads.entry("DEBUG"); entry.defaulted {
+		t.Errorf("expected no default value, got: %v", entry.def.value())
+	}
+}
+
+func TestLookupSchema(t *testing.T) {
+	src := MapSource{"HOST": "localhost"}
+	env := NewEnv(src)
+
+	env.StringLookup("HOST", Required())
+	if _, ok := env.Schema()["HOST"]; !ok {
+		t.Fatal("expected check of HOST in schema")
+	}
+
+	delete(src, "HOST")
+	expErr := errors.New("defenv: variable HOST is not set")
+	if err := env.ValidateAll(); fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+}
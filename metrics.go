@@ -0,0 +1,125 @@
+package defenv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Metrics counts reads of variables, defaults applied and errors per
+// variable name, so dashboards can spot services silently running on
+// defaults. Metrics is an http.Handler serving the counters in
+// the Prometheus text format:
+//
+// m := defenv.NewMetrics()
+// env := defenv.NewEnv(defenv.OS).WithMetrics(m)
+// http.Handle("/metrics/config", m)
+//
+// Errors are counted for ordinary getters, strict getters return
+// them to the caller
+type Metrics struct {
+	mu       sync.Mutex
+	reads    map[string]uint64
+	defaults map[string]uint64
+	errors   map[string]uint64
+}
+
+// NewMetrics returns Metrics with zero counters
+func NewMetrics() *Metrics {
+	return &Metrics{
+		reads:    make(map[string]uint64),
+		defaults: make(map[string]uint64),
+		errors:   make(map[string]uint64),
+	}
+}
+
+// WithMetrics returns a copy of the Env counting reads, defaults and errors
+// with m. Hooks set by OnRead and WithLogger are still called
+func (e *Env) WithMetrics(m *Metrics) *Env {
+	c := e.OnRead(chainRead(e.onRead, m.read))
+	c.onFail = chainFail(e.onFail, m.fail)
+	return c
+}
+
+func (m *Metrics) read(name, raw string, used Source, defaulted bool) {
+	m.mu.Lock()
+	m.reads[name]++
+	if defaulted {
+		m.defaults[name]++
+	}
+	m.mu.Unlock()
+}
+
+func (m *Metrics) fail(err error) {
+	name := errorVar(err)
+	if name == "" {
+		return
+	}
+
+	m.mu.Lock()
+	m.defaults[name]++
+	m.errors[name]++
+	m.mu.Unlock()
+}
+
+// WriteTo writes the counters to w in the Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	m.mu.Lock()
+	writeCounter(&buf, "defenv_reads_total", "Number of reads of a variable.", m.reads)
+	writeCounter(&buf, "defenv_defaults_total", "Number of times the default value of a variable was used.", m.defaults)
+	writeCounter(&buf, "defenv_errors_total", "Number of times a variable could not be parsed.", m.errors)
+	m.mu.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP writes the counters in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+func writeCounter(buf *bytes.Buffer, metric, help string, counts map[string]uint64) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s counter\n", metric, help, metric)
+	for _, name := range names {
+		fmt.Fprintf(buf, "%s{name=\"%s\"} %d\n", metric, labelEscaper.Replace(name), counts[name])
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// chainRead returns a read hook calling prev, if any, and then next
+func chainRead(prev, next func(name, raw string, used Source, defaulted bool)) func(name, raw string, used Source, defaulted bool) {
+	if prev == nil {
+		return next
+	}
+
+	return func(name, raw string, used Source, defaulted bool) {
+		prev(name, raw, used, defaulted)
+		next(name, raw, used, defaulted)
+	}
+}
+
+// chainFail returns a failure hook calling prev, if any, and then next
+func chainFail(prev, next func(error)) func(error) {
+	if prev == nil {
+		return next
+	}
+
+	return func(err error) {
+		prev(err)
+		next(err)
+	}
+}
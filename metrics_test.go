@@ -0,0 +1,76 @@
+package defenv
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	env := NewEnv(MapSource{"PORT": "8080", "WORKERS": "many", "LIMIT": "0"}).WithMetrics(m)
+
+	env.Int("PORT", 80)
+	env.Int("PORT", 80)
+	env.Int("WORKERS", 8)
+	env.PositiveInt("LIMIT", 10)
+	env.Duration("TIMEOUT", 0)
+	if _, err := env.IntStrict("WORKERS", 8); err == nil {
+		t.Error("expected error from strict getter")
+	}
+
+	expRes := `# HELP defenv_reads_total Number of reads of a variable.
+# TYPE defenv_reads_total counter
+defenv_reads_total{name="LIMIT"} 1
+defenv_reads_total{name="PORT"} 2
+defenv_reads_total{name="TIMEOUT"} 1
+defenv_reads_total{name="WORKERS"} 2
+# HELP defenv_defaults_total Number of times the default value of a variable was used.
+# TYPE defenv_defaults_total counter
+defenv_defaults_total{name="LIMIT"} 1
+defenv_defaults_total{name="TIMEOUT"} 1
+defenv_defaults_total{name="WORKERS"} 1
+# HELP defenv_errors_total Number of times a variable could not be parsed.
+# TYPE defenv_errors_total counter
+defenv_errors_total{name="LIMIT"} 1
+defenv_errors_total{name="WORKERS"} 1
+`
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != expRes {
+		t.Errorf("expected metrics:\n%s\ngot:\n%s", expRes, buf.String())
+	}
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Body.String() != expRes {
+		t.Errorf("expected response:\n%s\ngot:\n%s", expRes, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("unexpected content type: %s", ct)
+	}
+}
+
+func TestMetricsChainsHooks(t *testing.T) {
+	var (
+		reads, fails int
+		m            = NewMetrics()
+	)
+
+	env := NewEnv(MapSource{"PORT": "bad"}).
+		OnRead(func(name, raw string, used Source, defaulted bool) { reads++ })
+	env.onFail = func(error) { fails++ }
+	env = env.WithMetrics(m)
+
+	env.Int("PORT", 80)
+
+	if reads != 1 || fails != 1 {
+		t.Errorf("expected previous hooks to be called once, got: %d reads, %d fails", reads, fails)
+	}
+	if m.errors["PORT"] != 1 {
+		t.Errorf("expected 1 error, got: %d", m.errors["PORT"])
+	}
+}
@@ -0,0 +1,39 @@
+//go:build go1.18
+// +build go1.18
+
+package defenv
+
+import "sync"
+
+// Once returns a function calling loader on the first call and returning
+// its result and error on every call, so a configuration is loaded exactly
+// once however many goroutines need it. If loader panics, every call
+// panics with the same value:
+//
+// var config = defenv.Once(loadConfig)
+func Once[T any](loader func() (T, error)) func() (T, error) {
+	var (
+		once     sync.Once
+		res      T
+		err      error
+		panicked bool
+		p        interface{}
+	)
+
+	return func() (T, error) {
+		once.Do(func() {
+			panicked = true
+			defer func() {
+				p = recover()
+			}()
+			res, err = loader()
+			panicked = false
+		})
+
+		if panicked {
+			panic(p)
+		}
+
+		return res, err
+	}
+}
@@ -0,0 +1,97 @@
+//go:build go1.18
+// +build go1.18
+
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestOnce(t *testing.T) {
+	type config struct {
+		Port int
+	}
+
+	tt := []struct {
+		name   string
+		env    *Env
+		expRes config
+		expErr error
+	}{
+		{
+			name:   "loader succeeds then its result",
+			env:    NewEnv(MapSource{"PORT": "80"}),
+			expRes: config{Port: 80},
+		},
+		{
+			name:   "loader fails then its error",
+			env:    NewEnv(MapSource{"PORT": "abc"}),
+			expErr: errors.New(`defenv: parse PORT="abc" as int: invalid syntax`),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			load := Once(func() (config, error) {
+				calls++
+				port, err := tc.env.IntStrict("PORT", 8080)
+				if err != nil {
+					return config{}, err
+				}
+
+				return config{Port: port}, nil
+			})
+
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					res, err := load()
+					if res != tc.expRes {
+						t.Errorf("expected result: %v, got: %v", tc.expRes, res)
+					}
+					if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+						t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if calls != 1 {
+				t.Errorf("expected loader to be called once, got: %d", calls)
+			}
+		})
+	}
+}
+
+func TestOncePanic(t *testing.T) {
+	var calls int
+	load := Once(func() (int, error) {
+		calls++
+		return NewEnv(MapSource{"PORT": "abc"}).Strict(true).Int("PORT", 8080), nil
+	})
+
+	expPanic := `defenv: parse PORT="abc" as int: invalid syntax`
+	for i := 0; i < 2; i++ {
+		func() {
+			defer func() {
+				if r := recover(); fmt.Sprint(r) != expPanic {
+					t.Errorf("expected panic: %v, got: %v", expPanic, r)
+				}
+			}()
+
+			res, err := load()
+			t.Errorf("expected panic, got: %v, %v", res, err)
+		}()
+	}
+
+	if calls != 1 {
+		t.Errorf("expected loader to be called once, got: %d", calls)
+	}
+}
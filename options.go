@@ -0,0 +1,344 @@
+package defenv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Option changes behaviour of a single getter call:
+//
+// workers := defenv.Int("WORKER_NUMBER", 8, defenv.Min(1), defenv.Max(64))
+type Option func(*options)
+
+type options struct {
+	fallback     []string
+	trimSpace    bool
+	emptyAsUnset bool
+	hasMin       bool
+	min          float64
+	hasMax       bool
+	max          float64
+	clamping     bool
+	positive     bool
+	nonNeg       bool
+	literals     bool
+	required     bool
+}
+
+func newOptions(opts []Option) options {
+	if len(opts) == 0 {
+		return options{}
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Fallback sets names of variables consulted in order if the requested
+// variable is absent, before the default value is used:
+//
+// port := defenv.Int("SERVICE_PORT", 8080, defenv.Fallback("PORT"))
+func Fallback(names ...string) Option {
+	return func(o *options) {
+		o.fallback = append(o.fallback, names...)
+	}
+}
+
+// TrimSpace removes leading and trailing white space from the value
+// before parsing, the same way Env.WithTrimSpace does for every getter
+func TrimSpace() Option {
+	return func(o *options) {
+		o.trimSpace = true
+	}
+}
+
+// EmptyAsUnset makes a getter treat a variable set to an empty string
+// as absent and use the default value instead of failing to parse it,
+// the same way Env.WithEmptyAsUnset does for every getter
+func EmptyAsUnset() Option {
+	return func(o *options) {
+		o.emptyAsUnset = true
+	}
+}
+
+// Min sets the minimum allowed value of numeric getters. Values less than
+// min can not be parsed: ordinary getters return the default value and
+// strict getters return an error. Bounds of Duration getters are in
+// nanoseconds, e.g. Min(float64(time.Second)). Integer and Duration
+// values are compared with bounds exactly. Other getters ignore it
+func Min(min float64) Option {
+	return func(o *options) {
+		o.hasMin = true
+		o.min = min
+	}
+}
+
+// Max sets the maximum allowed value of numeric getters,
+// it works the same way as Min
+func Max(max float64) Option {
+	return func(o *options) {
+		o.hasMax = true
+		o.max = max
+	}
+}
+
+// Clamp sets bounds of numeric getters the same way as Min and Max,
+// but values out of bounds are replaced with the nearest bound
+// instead of being rejected
+func Clamp(min, max float64) Option {
+	return func(o *options) {
+		o.hasMin, o.min = true, min
+		o.hasMax, o.max = true, max
+		o.clamping = true
+	}
+}
+
+// Required makes getters fail with an error wrapping ErrNotSet
+// if the variable is absent: strict getters return the error and
+// ordinary getters return the default value
+func Required() Option {
+	return func(o *options) {
+		o.required = true
+	}
+}
+
+// IntLiterals makes integer getters accept literals the way Go source does:
+// base prefixes 0x, 0o and 0b, a leading 0 for octal, and underscores
+// between digits, e.g. "0o755", "0x1F" or "1_000_000"
+func IntLiterals() Option {
+	return func(o *options) {
+		o.literals = true
+	}
+}
+
+// companion returns a copy of options for reading companion
+// variables, which have no fallbacks and are never required
+func (o options) companion() options {
+	o.fallback = nil
+	o.required = false
+	return o
+}
+
+// checkRange returns an error if v parsed from raw value of variable
+// named name is out of bounds set by Min and Max. NaN is never in range,
+// infinity is out of range if any bound is set
+func (o options) checkRange(name, raw string, v float64, format func(float64) string) error {
+	if math.IsNaN(v) {
+		return &varError{name: name, code: CodeOutOfRange, msg: fmt.Sprintf("defenv: %s=%s is not a number", name, quoteValue(name, raw))}
+	}
+
+	if math.IsInf(v, 0) && (o.positive || o.nonNeg || o.hasMin || o.hasMax) {
+		return &varError{name: name, code: CodeOutOfRange, msg: fmt.Sprintf("defenv: %s=%s is infinite", name, quoteValue(name, raw))}
+	}
+
+	return o.rangeError(name, raw, v <= 0, v < 0, o.hasMin && v < o.min, o.hasMax && v > o.max, format)
+}
+
+// checkIntRange is like checkRange, but compares v with the bounds exactly,
+// integers above 2^53 are not rounded to float64
+func (o options) checkIntRange(name, raw string, v int64, format func(float64) string) error {
+	return o.rangeError(name, raw, v <= 0, v < 0, o.hasMin && intBelow(v, o.min), o.hasMax && intAbove(v, o.max), format)
+}
+
+// checkUintRange is like checkIntRange for unsigned integers
+func (o options) checkUintRange(name, raw string, v uint64, format func(float64) string) error {
+	return o.rangeError(name, raw, v == 0, false, o.hasMin && uintBelow(v, o.min), o.hasMax && uintAbove(v, o.max), format)
+}
+
+// rangeError returns an error describing the first violated bound
+func (o options) rangeError(name, raw string, notPositive, negative, below, above bool, format func(float64) string) error {
+	if o.positive && notPositive {
+		return &varError{name: name, code: CodeOutOfRange, msg: fmt.Sprintf("defenv: %s=%s is not positive", name, quoteValue(name, raw))}
+	}
+
+	if o.nonNeg && negative {
+		return &varError{name: name, code: CodeOutOfRange, msg: fmt.Sprintf("defenv: %s=%s is negative", name, quoteValue(name, raw))}
+	}
+
+	if o.hasMin && o.hasMax && (below || above) {
+		return &varError{name: name, code: CodeOutOfRange, msg: fmt.Sprintf("defenv: %s=%s is out of range [%s, %s]", name, quoteValue(name, raw), format(o.min), format(o.max))}
+	}
+
+	if below {
+		return &varError{name: name, code: CodeOutOfRange, msg: fmt.Sprintf("defenv: %s=%s is less than minimum %s", name, quoteValue(name, raw), format(o.min))}
+	}
+
+	if above {
+		return &varError{name: name, code: CodeOutOfRange, msg: fmt.Sprintf("defenv: %s=%s is greater than maximum %s", name, quoteValue(name, raw), format(o.max))}
+	}
+
+	return nil
+}
+
+// clamp returns the nearest bound and true if clamping is enabled
+// and v is out of bounds
+func (o options) clamp(v float64) (float64, bool) {
+	if !o.clamping {
+		return v, false
+	}
+
+	if o.hasMin && v < o.min {
+		return o.min, true
+	}
+
+	if o.hasMax && v > o.max {
+		return o.max, true
+	}
+
+	return v, false
+}
+
+// clampInt is like clamp for integers, it returns the nearest integer
+// within bounds. Bounds beyond int64 are left to checkIntRange
+func (o options) clampInt(v int64) (int64, bool) {
+	if !o.clamping {
+		return v, false
+	}
+
+	if o.hasMin && intBelow(v, o.min) {
+		c, ok := ceilInt(o.min)
+		return c, ok
+	}
+
+	if o.hasMax && intAbove(v, o.max) {
+		f, ok := floorInt(o.max)
+		return f, ok
+	}
+
+	return v, false
+}
+
+// clampUint is like clampInt for unsigned integers
+func (o options) clampUint(v uint64) (uint64, bool) {
+	if !o.clamping {
+		return v, false
+	}
+
+	if o.hasMin && uintBelow(v, o.min) {
+		c, ok := ceilUint(o.min)
+		return c, ok
+	}
+
+	if o.hasMax && uintAbove(v, o.max) {
+		f, ok := floorUint(o.max)
+		return f, ok
+	}
+
+	return v, false
+}
+
+// 2^63 and 2^64 are exact in float64, unlike math.MaxInt64 and math.MaxUint64
+const (
+	twoTo63 = float64(1 << 63)
+	twoTo64 = float64(1 << 64)
+)
+
+// ceilInt returns the least int64 not less than f
+// and false if there is no such int64
+func ceilInt(f float64) (int64, bool) {
+	switch {
+	case f <= -twoTo63:
+		return math.MinInt64, true
+	case f < twoTo63:
+		return int64(math.Ceil(f)), true
+	}
+
+	return 0, false
+}
+
+// floorInt returns the greatest int64 not greater than f
+// and false if there is no such int64
+func floorInt(f float64) (int64, bool) {
+	switch {
+	case f >= twoTo63:
+		return math.MaxInt64, true
+	case f >= -twoTo63:
+		return int64(math.Floor(f)), true
+	}
+
+	return 0, false
+}
+
+// ceilUint is like ceilInt for uint64
+func ceilUint(f float64) (uint64, bool) {
+	switch {
+	case f <= 0:
+		return 0, true
+	case f < twoTo64:
+		return uint64(math.Ceil(f)), true
+	}
+
+	return 0, false
+}
+
+// floorUint is like floorInt for uint64
+func floorUint(f float64) (uint64, bool) {
+	switch {
+	case f >= twoTo64:
+		return math.MaxUint64, true
+	case f >= 0:
+		return uint64(math.Floor(f)), true
+	}
+
+	return 0, false
+}
+
+// intBelow reports whether v < min without rounding v to float64
+func intBelow(v int64, min float64) bool {
+	c, ok := ceilInt(min)
+	return !ok || v < c
+}
+
+// intAbove reports whether v > max without rounding v to float64
+func intAbove(v int64, max float64) bool {
+	f, ok := floorInt(max)
+	return !ok || v > f
+}
+
+// uintBelow reports whether v < min without rounding v to float64
+func uintBelow(v uint64, min float64) bool {
+	c, ok := ceilUint(min)
+	return !ok || v < c
+}
+
+// uintAbove reports whether v > max without rounding v to float64
+func uintAbove(v uint64, max float64) bool {
+	f, ok := floorUint(max)
+	return !ok || v > f
+}
+
+// parseInt parses a decimal integer or an integer literal
+// if IntLiterals is set
+func (o options) parseInt(s string, bitSize int) (int64, error) {
+	base := 10
+	if o.literals {
+		base = 0
+	}
+
+	return strconv.ParseInt(s, base, bitSize)
+}
+
+// parseUint parses a decimal unsigned integer or an integer literal
+// if IntLiterals is set
+func (o options) parseUint(s string, bitSize int) (uint64, error) {
+	base := 10
+	if o.literals {
+		base = 0
+	}
+
+	return strconv.ParseUint(s, base, bitSize)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func formatDuration(f float64) string {
+	return time.Duration(f).String()
+}
@@ -0,0 +1,338 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestOptionsInt(t *testing.T) {
+	testCases := []struct {
+		name         string
+		envValue     string
+		opts         []Option
+		defaultValue int
+		expRes       int
+		expErr       error
+	}{
+		{
+			name:         "success then value is within bounds",
+			envValue:     "10",
+			opts:         []Option{Min(1), Max(64)},
+			defaultValue: 8,
+			expRes:       10,
+		},
+		{
+			name:         "success then value is equal to bounds",
+			envValue:     "64",
+			opts:         []Option{Min(64), Max(64)},
+			defaultValue: 8,
+			expRes:       64,
+		},
+		{
+			name:         "fail then value is less than lower bound",
+			envValue:     "0",
+			opts:         []Option{Min(1), Max(64)},
+			defaultValue: 8,
+			expErr:       errors.New(`defenv: WORKER_NUMBER="0" is out of range [1, 64]`),
+		},
+		{
+			name:         "fail then value is greater than upper bound",
+			envValue:     "100",
+			opts:         []Option{Min(1), Max(64)},
+			defaultValue: 8,
+			expErr:       errors.New(`defenv: WORKER_NUMBER="100" is out of range [1, 64]`),
+		},
+		{
+			name:         "success then value is empty and empty is allowed",
+			envValue:     "",
+			opts:         []Option{EmptyAsUnset()},
+			defaultValue: 8,
+			expRes:       8,
+		},
+		{
+			name:         "success then value has spaces and spaces are trimmed",
+			envValue:     " 10\n",
+			opts:         []Option{TrimSpace()},
+			defaultValue: 8,
+			expRes:       10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := NewEnv(MapSource{"WORKER_NUMBER": tc.envValue})
+
+			res, err := env.IntStrict("WORKER_NUMBER", tc.defaultValue, tc.opts...)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected result: %d, got: %d", tc.expRes, res)
+			}
+
+			expRes := tc.expRes
+			if tc.expErr != nil {
+				expRes = tc.defaultValue
+			}
+			if res := env.Int("WORKER_NUMBER", tc.defaultValue, tc.opts...); res != expRes {
+				t.Errorf("expected result of ordinary method: %d, got: %d", expRes, res)
+			}
+		})
+	}
+}
+
+func TestOptionsDuration(t *testing.T) {
+	env := NewEnv(MapSource{"TIMEOUT": "500ms"})
+
+	_, err := env.DurationStrict("TIMEOUT", 5*time.Second, Min(float64(time.Second)))
+	expErr := errors.New(`defenv: TIMEOUT="500ms" is less than minimum 1s`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+
+	res := env.Duration("TIMEOUT", 5*time.Second, Max(float64(time.Second)))
+	if res != 500*time.Millisecond {
+		t.Errorf("expected result: %s, got: %s", 500*time.Millisecond, res)
+	}
+}
+
+func TestOptionsFloat64(t *testing.T) {
+	env := NewEnv(MapSource{"RATIO": "1.5"})
+
+	_, err := env.Float64Strict("RATIO", 0.5, Max(1))
+	expErr := errors.New(`defenv: RATIO="1.5" is greater than maximum 1`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+
+	env = NewEnv(MapSource{"NAN": "NaN", "INF": "+Inf"})
+
+	_, err = env.Float64Strict("NAN", 1, Min(0), Max(10))
+	expErr = errors.New(`defenv: NAN="NaN" is not a number`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+
+	_, err = env.Float64Strict("INF", 1, Min(0))
+	expErr = errors.New(`defenv: INF="+Inf" is infinite`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+
+	if res, err := env.Float64Strict("INF", 1); err != nil || !math.IsInf(res, 1) {
+		t.Errorf("expected value without bounds: %g, got: %g (%v)", math.Inf(1), res, err)
+	}
+}
+
+func TestOptionsWithPrefix(t *testing.T) {
+	env := NewEnv(MapSource{"APP_PORT": "80"}).WithPrefix("APP_")
+
+	_, err := env.IntStrict("PORT", 8080, Min(1024))
+	expErr := errors.New(`defenv: APP_PORT="80" is less than minimum 1024`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+}
+
+func TestOptionsString(t *testing.T) {
+	env := NewEnv(MapSource{"HOST": "  localhost  ", "EMPTY": ""})
+
+	if res := env.String("HOST", "", TrimSpace()); res != "localhost" {
+		t.Errorf("expected result: %q, got: %q", "localhost", res)
+	}
+	if res := env.String("EMPTY", "default", EmptyAsUnset()); res != "default" {
+		t.Errorf("expected result: %q, got: %q", "default", res)
+	}
+	if res := env.String("EMPTY", "default"); res != "" {
+		t.Errorf("expected result: %q, got: %q", "", res)
+	}
+}
+
+func TestOptionsFallback(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source MapSource
+		expRes int
+	}{
+		{
+			name:   "success then specific variable is set",
+			source: MapSource{"SERVICE_PORT": "9090", "PORT": "8000"},
+			expRes: 9090,
+		},
+		{
+			name:   "success then only generic variable is set",
+			source: MapSource{"PORT": "8000"},
+			expRes: 8000,
+		},
+		{
+			name:   "success then the last fallback is set",
+			source: MapSource{"HTTP_PORT": "7000"},
+			expRes: 7000,
+		},
+		{
+			name:   "success then nothing is set",
+			source: MapSource{},
+			expRes: 8080,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NewEnv(tc.source).Int("SERVICE_PORT", 8080, Fallback("PORT"), Fallback("HTTP_PORT"))
+			if res != tc.expRes {
+				t.Errorf("expected result: %d, got: %d", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestOptionsFallbackCompanions(t *testing.T) {
+	env := NewEnv(MapSource{"ARGS": "-v", "ARGS_APPEND": "-x"})
+
+	res := env.Command("CMD", []string{"run"}, Fallback("ARGS"))
+	exp := []string{"-v"}
+	if fmt.Sprint(res) != fmt.Sprint(exp) {
+		t.Errorf("expected result: %q, got: %q", exp, res)
+	}
+}
+
+func TestOptionsClamp(t *testing.T) {
+	env := NewEnv(MapSource{
+		"LOW":     "0",
+		"HIGH":    "1000",
+		"OK":      "16",
+		"TIMEOUT": "1h",
+		"RATIO":   "-0.5",
+	})
+
+	for _, tc := range []struct {
+		name   string
+		key    string
+		expRes int
+	}{
+		{name: "clamp to minimum then value is too low", key: "LOW", expRes: 1},
+		{name: "clamp to maximum then value is too high", key: "HIGH", expRes: 64},
+		{name: "keep value then value is within bounds", key: "OK", expRes: 16},
+		{name: "use default value then value is absent", key: "ABSENT", expRes: 8},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := env.IntStrict(tc.key, 8, Clamp(1, 64))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected result: %d, got: %d", tc.expRes, res)
+			}
+		})
+	}
+
+	if res := env.Duration("TIMEOUT", time.Second, Clamp(0, float64(time.Minute))); res != time.Minute {
+		t.Errorf("expected result: %s, got: %s", time.Minute, res)
+	}
+	if res := env.Float64("RATIO", 0.5, Clamp(0, 1)); res != 0 {
+		t.Errorf("expected result: %g, got: %g", 0.0, res)
+	}
+}
+
+func TestOptionsIntegerBounds(t *testing.T) {
+	env := NewEnv(MapSource{
+		"BIG":      "9007199254740993",
+		"HUGE":     "18446744073709551615",
+		"NEGATIVE": "-9223372036854775808",
+		"HALF":     "1",
+	})
+
+	_, err := env.Int64Strict("BIG", 0, Max(9007199254740992))
+	expErr := errors.New(`defenv: BIG="9007199254740993" is greater than maximum 9.007199254740992e+15`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+
+	if res, err := env.Int64Strict("BIG", 0, Clamp(0, 9007199254740992)); err != nil || res != 9007199254740992 {
+		t.Errorf("expected result: %d, got: %d, %v", int64(9007199254740992), res, err)
+	}
+
+	_, err = env.Uint64Strict("HUGE", 0, Max(float64(math.MaxUint64)))
+	if err != nil {
+		t.Errorf("expected maximum uint64 to be within 2^64, got: %v", err)
+	}
+
+	_, err = env.Int64Strict("NEGATIVE", 0, Min(-1e19))
+	if err != nil {
+		t.Errorf("expected minimum int64 to be within -1e19, got: %v", err)
+	}
+
+	if res, err := env.IntStrict("HALF", 0, Clamp(1.5, 2.5)); err != nil || res != 2 {
+		t.Errorf("expected result: %d, got: %d, %v", 2, res, err)
+	}
+}
+
+func TestOptionsIntLiterals(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		envValue string
+		expRes   int64
+		expErr   error
+	}{
+		{name: `hex literal "0x1F"`, envValue: "0x1F", expRes: 31},
+		{name: `octal literal "0o755"`, envValue: "0o755", expRes: 493},
+		{name: `octal literal "0755"`, envValue: "0755", expRes: 493},
+		{name: `binary literal "0b1010"`, envValue: "0b1010", expRes: 10},
+		{name: `negative hex literal "-0x10"`, envValue: "-0x10", expRes: -16},
+		{name: `decimal literal "1_000_000"`, envValue: "1_000_000", expRes: 1000000},
+		{name: `hex literal "0x_FF_FF"`, envValue: "0x_FF_FF", expRes: 65535},
+		{name: `zero "0"`, envValue: "0", expRes: 0},
+		{
+			name:     `fail then literal is "1__000"`,
+			envValue: "1__000",
+			expErr:   errors.New(`defenv: parse MASK="1__000" as int64: invalid syntax`),
+		},
+		{
+			name:     `fail then literal is "1000_"`,
+			envValue: "1000_",
+			expErr:   errors.New(`defenv: parse MASK="1000_" as int64: invalid syntax`),
+		},
+		{
+			name:     `fail then literal is "0x"`,
+			envValue: "0x",
+			expErr:   errors.New(`defenv: parse MASK="0x" as int64: invalid syntax`),
+		},
+		{
+			name:     `fail then literal is "0b102"`,
+			envValue: "0b102",
+			expErr:   errors.New(`defenv: parse MASK="0b102" as int64: invalid syntax`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env := NewEnv(MapSource{"MASK": tc.envValue})
+
+			res, err := env.Int64Strict("MASK", 0, IntLiterals())
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected result: %d, got: %d", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestOptionsIntLiteralsUint(t *testing.T) {
+	env := NewEnv(MapSource{"PERM": "0o755", "NEGATIVE": "-0x1"})
+
+	if res := env.Uint("PERM", 0644, IntLiterals()); res != 0755 {
+		t.Errorf("expected result: %o, got: %o", 0755, res)
+	}
+	if res := env.Uint("PERM", 0644); res != 0644 {
+		t.Errorf("expected result without option: %o, got: %o", 0644, res)
+	}
+
+	_, err := env.Uint64Strict("NEGATIVE", 0, IntLiterals())
+	expErr := errors.New(`defenv: parse NEGATIVE="-0x1" as uint64: invalid syntax`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+}
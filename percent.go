@@ -0,0 +1,96 @@
+package defenv
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Percent extracts percentage from environment variable named name
+// and returns defaultValue if it is absent or can not be parsed.
+// See PercentStrict for accepted formats
+func Percent(name string, defaultValue float64, opts ...Option) float64 {
+	return std.Percent(name, defaultValue, opts...)
+}
+
+// PercentStrict extracts percentage from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed, the method returns an error.
+//
+// The result is in percents and the % suffix is optional: "85%" and "85"
+// both mean 85, "0.5" means 0.5. Negative values are rejected
+func PercentStrict(name string, defaultValue float64, opts ...Option) (float64, error) {
+	return std.PercentStrict(name, defaultValue, opts...)
+}
+
+// Percent extracts percentage from variable named name
+// and returns defaultValue if it is absent or can not be parsed.
+// See PercentStrict for accepted formats
+func (e *Env) Percent(name string, defaultValue float64, opts ...Option) float64 {
+	res, err := e.PercentStrict(name, defaultValue, opts...)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return res
+}
+
+// PercentStrict extracts percentage from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error.
+//
+// The result is in percents and the % suffix is optional: "85%" and "85"
+// both mean 85, "0.5" means 0.5. Negative values are rejected
+func (e *Env) PercentStrict(name string, defaultValue float64, opts ...Option) (float64, error) {
+	if !e.registered([]string{name}) {
+		opts := append([]Option(nil), opts...)
+		e.register([]string{name}, func(env *Env) error {
+			_, err := env.PercentStrict(name, defaultValue, opts...)
+			return err
+		})
+	}
+
+	o := newOptions(opts)
+	o.nonNeg = true
+	name, strVal, ok, err := e.value([]string{name}, float64Def(defaultValue), o)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return defaultValue, nil
+	}
+
+	f, err := parsePercent(strVal)
+	if err != nil {
+		return 0, &ParseError{Var: name, Raw: strVal, Type: "float64", Err: err}
+	}
+
+	if c, ok := o.clamp(f); ok {
+		f = c
+	}
+
+	if err := o.checkRange(name, strVal, f, formatFloat); err != nil {
+		return 0, err
+	}
+
+	return f, nil
+}
+
+// parsePercent parses a percentage with optional % suffix,
+// NaN and infinity are rejected
+func parsePercent(s string) (float64, error) {
+	if strings.HasSuffix(s, "%") {
+		s = strings.TrimSpace(s[:len(s)-1])
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errNotFinite
+	}
+
+	return f, nil
+}
@@ -0,0 +1,120 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPercentStrict(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		envValue     string
+		defaultValue float64
+		expRes       float64
+		expErr       error
+	}{
+		{
+			name:         `success then environment value is "85%"`,
+			envValue:     "85%",
+			defaultValue: 50,
+			expRes:       85,
+		},
+		{
+			name:         `success then environment value is "85"`,
+			envValue:     "85",
+			defaultValue: 50,
+			expRes:       85,
+		},
+		{
+			name:         `success then environment value is "0.5"`,
+			envValue:     "0.5",
+			defaultValue: 50,
+			expRes:       0.5,
+		},
+		{
+			name:         `success then environment value is "1"`,
+			envValue:     "1",
+			defaultValue: 50,
+			expRes:       1,
+		},
+		{
+			name:         `success then environment value is "1.5"`,
+			envValue:     "1.5",
+			defaultValue: 50,
+			expRes:       1.5,
+		},
+		{
+			name:         `fail then environment value is "-5"`,
+			envValue:     "-5",
+			defaultValue: 50,
+			expErr:       errors.New(`defenv: CPU_THRESHOLD="-5" is negative`),
+		},
+		{
+			name:         `fail then environment value is "-5%"`,
+			envValue:     "-5%",
+			defaultValue: 50,
+			expErr:       errors.New(`defenv: CPU_THRESHOLD="-5%" is negative`),
+		},
+		{
+			name:         `success then environment value is "0.5%"`,
+			envValue:     "0.5%",
+			defaultValue: 50,
+			expRes:       0.5,
+		},
+		{
+			name:         `success then environment value is "150%"`,
+			envValue:     "150%",
+			defaultValue: 50,
+			expRes:       150,
+		},
+		{
+			name:         `fail then environment value is "85 %%"`,
+			envValue:     "85 %%",
+			defaultValue: 50,
+			expErr:       errors.New(`defenv: parse CPU_THRESHOLD="85 %%" as float64: invalid syntax`),
+		},
+		{
+			name:         `fail then environment value is "+Inf"`,
+			envValue:     "+Inf",
+			defaultValue: 50,
+			expErr:       errors.New(`defenv: parse CPU_THRESHOLD="+Inf" as float64: not a finite number`),
+		},
+		{
+			name:         `fail then environment value is "NaN%"`,
+			envValue:     "NaN%",
+			defaultValue: 50,
+			expErr:       errors.New(`defenv: parse CPU_THRESHOLD="NaN%" as float64: not a finite number`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env := NewEnv(MapSource{"CPU_THRESHOLD": tc.envValue})
+
+			res, err := env.PercentStrict("CPU_THRESHOLD", tc.defaultValue)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %g, got: %g", tc.expRes, res)
+			}
+
+			expRes := tc.expRes
+			if tc.expErr != nil {
+				expRes = tc.defaultValue
+			}
+			if res := env.Percent("CPU_THRESHOLD", tc.defaultValue); res != expRes {
+				t.Errorf("expected value of ordinary method: %g, got: %g", expRes, res)
+			}
+		})
+	}
+}
+
+func TestPercentStrictWithMax(t *testing.T) {
+	env := NewEnv(MapSource{"CPU_THRESHOLD": "150%"})
+
+	_, err := env.PercentStrict("CPU_THRESHOLD", 50, Max(100))
+	expErr := errors.New(`defenv: CPU_THRESHOLD="150%" is greater than maximum 100`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+}
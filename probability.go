@@ -0,0 +1,94 @@
+package defenv
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Probability extracts probability from environment variable named name
+// and returns defaultValue if it is absent, can not be parsed or is out
+// of range [0, 1]. The value is a fraction like "0.25" or a percentage like "25%"
+func Probability(name string, defaultValue float64, opts ...Option) float64 {
+	return std.Probability(name, defaultValue, opts...)
+}
+
+// ProbabilityStrict extracts probability from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed or is out of range [0, 1], the method returns an error
+func ProbabilityStrict(name string, defaultValue float64, opts ...Option) (float64, error) {
+	return std.ProbabilityStrict(name, defaultValue, opts...)
+}
+
+// Probability extracts probability from variable named name
+// and returns defaultValue if it is absent, can not be parsed or is out
+// of range [0, 1]. The value is a fraction like "0.25" or a percentage like "25%"
+func (e *Env) Probability(name string, defaultValue float64, opts ...Option) float64 {
+	res, err := e.ProbabilityStrict(name, defaultValue, opts...)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return res
+}
+
+// ProbabilityStrict extracts probability from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed or is out of range [0, 1], the method returns an error
+func (e *Env) ProbabilityStrict(name string, defaultValue float64, opts ...Option) (float64, error) {
+	if !e.registered([]string{name}) {
+		opts := append([]Option(nil), opts...)
+		e.register([]string{name}, func(env *Env) error {
+			_, err := env.ProbabilityStrict(name, defaultValue, opts...)
+			return err
+		})
+	}
+
+	o := newOptions(withOptions(opts, Min(0), Max(1)))
+	name, strVal, ok, err := e.value([]string{name}, float64Def(defaultValue), o)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return defaultValue, nil
+	}
+
+	f, err := parseProbability(strVal)
+	if err != nil {
+		return 0, &ParseError{Var: name, Raw: strVal, Type: "float64", Err: err}
+	}
+
+	if c, ok := o.clamp(f); ok {
+		f = c
+	}
+
+	if err := o.checkRange(name, strVal, f, formatFloat); err != nil {
+		return 0, err
+	}
+
+	return f, nil
+}
+
+// parseProbability parses a fraction or a percentage with % suffix,
+// NaN and infinity are rejected
+func parseProbability(s string) (float64, error) {
+	percent := strings.HasSuffix(s, "%")
+	if percent {
+		s = strings.TrimSpace(s[:len(s)-1])
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errNotFinite
+	}
+
+	if percent {
+		return f / 100, nil
+	}
+
+	return f, nil
+}
@@ -0,0 +1,110 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestProbabilityStrict(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		envValue     string
+		defaultValue float64
+		expRes       float64
+		expErr       error
+	}{
+		{
+			name:         `success then environment value is "0.25"`,
+			envValue:     "0.25",
+			defaultValue: 0.1,
+			expRes:       0.25,
+		},
+		{
+			name:         `success then environment value is "25%"`,
+			envValue:     "25%",
+			defaultValue: 0.1,
+			expRes:       0.25,
+		},
+		{
+			name:         `success then environment value is "1"`,
+			envValue:     "1",
+			defaultValue: 0.1,
+			expRes:       1,
+		},
+		{
+			name:         `success then environment value is "0"`,
+			envValue:     "0",
+			defaultValue: 0.1,
+			expRes:       0,
+		},
+		{
+			name:         `fail then environment value is "1.5"`,
+			envValue:     "1.5",
+			defaultValue: 0.1,
+			expErr:       errors.New(`defenv: TRACE_SAMPLE_RATE="1.5" is out of range [0, 1]`),
+		},
+		{
+			name:         `fail then environment value is "150%"`,
+			envValue:     "150%",
+			defaultValue: 0.1,
+			expErr:       errors.New(`defenv: TRACE_SAMPLE_RATE="150%" is out of range [0, 1]`),
+		},
+		{
+			name:         `fail then environment value is "-0.1"`,
+			envValue:     "-0.1",
+			defaultValue: 0.1,
+			expErr:       errors.New(`defenv: TRACE_SAMPLE_RATE="-0.1" is out of range [0, 1]`),
+		},
+		{
+			name:         `fail then environment value is "bad%"`,
+			envValue:     "bad%",
+			defaultValue: 0.1,
+			expErr:       errors.New(`defenv: parse TRACE_SAMPLE_RATE="bad%" as float64: invalid syntax`),
+		},
+		{
+			name:         `fail then environment value is "NaN"`,
+			envValue:     "NaN",
+			defaultValue: 0.1,
+			expErr:       errors.New(`defenv: parse TRACE_SAMPLE_RATE="NaN" as float64: not a finite number`),
+		},
+		{
+			name:         `fail then environment value is "NaN%"`,
+			envValue:     "NaN%",
+			defaultValue: 0.1,
+			expErr:       errors.New(`defenv: parse TRACE_SAMPLE_RATE="NaN%" as float64: not a finite number`),
+		},
+		{
+			name:         `fail then environment value is "+Inf"`,
+			envValue:     "+Inf",
+			defaultValue: 0.1,
+			expErr:       errors.New(`defenv: parse TRACE_SAMPLE_RATE="+Inf" as float64: not a finite number`),
+		},
+		{
+			name:         `fail then environment value is "-Inf%"`,
+			envValue:     "-Inf%",
+			defaultValue: 0.1,
+			expErr:       errors.New(`defenv: parse TRACE_SAMPLE_RATE="-Inf%" as float64: not a finite number`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env := NewEnv(MapSource{"TRACE_SAMPLE_RATE": tc.envValue})
+
+			res, err := env.ProbabilityStrict("TRACE_SAMPLE_RATE", tc.defaultValue)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %g, got: %g", tc.expRes, res)
+			}
+
+			expRes := tc.expRes
+			if tc.expErr != nil {
+				expRes = tc.defaultValue
+			}
+			if res := env.Probability("TRACE_SAMPLE_RATE", tc.defaultValue); res != expRes {
+				t.Errorf("expected value of ordinary method: %g, got: %g", expRes, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package defenv
+
+import "time"
+
+// IntInRange extracts int value from environment variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is out of range [min, max]
+func IntInRange(name string, defaultValue, min, max int, opts ...Option) int {
+	return std.IntInRange(name, defaultValue, min, max, opts...)
+}
+
+// IntInRangeStrict extracts int value from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed or is out of range [min, max], the method returns an error
+func IntInRangeStrict(name string, defaultValue, min, max int, opts ...Option) (int, error) {
+	return std.IntInRangeStrict(name, defaultValue, min, max, opts...)
+}
+
+// DurationInRange extracts time.Duration value from environment variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is out of range [min, max]
+func DurationInRange(name string, defaultValue, min, max time.Duration, opts ...Option) time.Duration {
+	return std.DurationInRange(name, defaultValue, min, max, opts...)
+}
+
+// DurationInRangeStrict extracts time.Duration value from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed or is out of range [min, max], the method returns an error
+func DurationInRangeStrict(name string, defaultValue, min, max time.Duration, opts ...Option) (time.Duration, error) {
+	return std.DurationInRangeStrict(name, defaultValue, min, max, opts...)
+}
+
+// IntInRange extracts int value from variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is out of range [min, max]
+func (e *Env) IntInRange(name string, defaultValue, min, max int, opts ...Option) int {
+	return e.Int(name, defaultValue, withOptions(opts, Min(float64(min)), Max(float64(max)))...)
+}
+
+// IntInRangeStrict extracts int value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed or is out of range [min, max], the method returns an error
+func (e *Env) IntInRangeStrict(name string, defaultValue, min, max int, opts ...Option) (int, error) {
+	return e.IntStrict(name, defaultValue, withOptions(opts, Min(float64(min)), Max(float64(max)))...)
+}
+
+// DurationInRange extracts time.Duration value from variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is out of range [min, max]
+func (e *Env) DurationInRange(name string, defaultValue, min, max time.Duration, opts ...Option) time.Duration {
+	return e.Duration(name, defaultValue, withOptions(opts, Min(float64(min)), Max(float64(max)))...)
+}
+
+// DurationInRangeStrict extracts time.Duration value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed or is out of range [min, max], the method returns an error
+func (e *Env) DurationInRangeStrict(name string, defaultValue, min, max time.Duration, opts ...Option) (time.Duration, error) {
+	return e.DurationStrict(name, defaultValue, withOptions(opts, Min(float64(min)), Max(float64(max)))...)
+}
+
+// withOptions returns a copy of opts with extra options appended,
+// so they override options set by the caller
+func withOptions(opts []Option, extra ...Option) []Option {
+	return append(append([]Option{}, opts...), extra...)
+}
+
+// PositiveInt extracts int value from environment variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is not greater than zero
+func PositiveInt(name string, defaultValue int, opts ...Option) int {
+	return std.PositiveInt(name, defaultValue, opts...)
+}
+
+// PositiveIntStrict extracts int value from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed or is not greater than zero, the method returns an error
+func PositiveIntStrict(name string, defaultValue int, opts ...Option) (int, error) {
+	return std.PositiveIntStrict(name, defaultValue, opts...)
+}
+
+// NonNegativeInt extracts int value from environment variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is less than zero
+func NonNegativeInt(name string, defaultValue int, opts ...Option) int {
+	return std.NonNegativeInt(name, defaultValue, opts...)
+}
+
+// NonNegativeIntStrict extracts int value from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed or is less than zero, the method returns an error
+func NonNegativeIntStrict(name string, defaultValue int, opts ...Option) (int, error) {
+	return std.NonNegativeIntStrict(name, defaultValue, opts...)
+}
+
+// PositiveDuration extracts time.Duration value from environment variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is not greater than zero
+func PositiveDuration(name string, defaultValue time.Duration, opts ...Option) time.Duration {
+	return std.PositiveDuration(name, defaultValue, opts...)
+}
+
+// PositiveDurationStrict extracts time.Duration value from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed or is not greater than zero, the method returns an error
+func PositiveDurationStrict(name string, defaultValue time.Duration, opts ...Option) (time.Duration, error) {
+	return std.PositiveDurationStrict(name, defaultValue, opts...)
+}
+
+// PositiveInt extracts int value from variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is not greater than zero
+func (e *Env) PositiveInt(name string, defaultValue int, opts ...Option) int {
+	return e.Int(name, defaultValue, withOptions(opts, positive)...)
+}
+
+// PositiveIntStrict extracts int value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed or is not greater than zero, the method returns an error
+func (e *Env) PositiveIntStrict(name string, defaultValue int, opts ...Option) (int, error) {
+	return e.IntStrict(name, defaultValue, withOptions(opts, positive)...)
+}
+
+// NonNegativeInt extracts int value from variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is less than zero
+func (e *Env) NonNegativeInt(name string, defaultValue int, opts ...Option) int {
+	return e.Int(name, defaultValue, withOptions(opts, nonNegative)...)
+}
+
+// NonNegativeIntStrict extracts int value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed or is less than zero, the method returns an error
+func (e *Env) NonNegativeIntStrict(name string, defaultValue int, opts ...Option) (int, error) {
+	return e.IntStrict(name, defaultValue, withOptions(opts, nonNegative)...)
+}
+
+// PositiveDuration extracts time.Duration value from variable named name
+// and returns defaultValue if it is absent, can not be parsed
+// or is not greater than zero
+func (e *Env) PositiveDuration(name string, defaultValue time.Duration, opts ...Option) time.Duration {
+	return e.Duration(name, defaultValue, withOptions(opts, positive)...)
+}
+
+// PositiveDurationStrict extracts time.Duration value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed or is not greater than zero, the method returns an error
+func (e *Env) PositiveDurationStrict(name string, defaultValue time.Duration, opts ...Option) (time.Duration, error) {
+	return e.DurationStrict(name, defaultValue, withOptions(opts, positive)...)
+}
+
+func positive(o *options) {
+	o.positive = true
+}
+
+func nonNegative(o *options) {
+	o.nonNeg = true
+}
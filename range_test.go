@@ -0,0 +1,156 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIntInRangeStrict(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		envValue     string
+		defaultValue int
+		expRes       int
+		expErr       error
+	}{
+		{
+			name:         `success then environment value is "16"`,
+			envValue:     "16",
+			defaultValue: 8,
+			expRes:       16,
+		},
+		{
+			name:         `success then environment value is equal to bound`,
+			envValue:     "64",
+			defaultValue: 8,
+			expRes:       64,
+		},
+		{
+			name:         `fail then environment value is "0"`,
+			envValue:     "0",
+			defaultValue: 8,
+			expErr:       errors.New(`defenv: WORKER_NUMBER="0" is out of range [1, 64]`),
+		},
+		{
+			name:         `fail then environment value is "65"`,
+			envValue:     "65",
+			defaultValue: 8,
+			expErr:       errors.New(`defenv: WORKER_NUMBER="65" is out of range [1, 64]`),
+		},
+		{
+			name:         `fail then environment value is "bad"`,
+			envValue:     "bad",
+			defaultValue: 8,
+			expErr:       errors.New(`defenv: parse WORKER_NUMBER="bad" as int: invalid syntax`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env := NewEnv(MapSource{"WORKER_NUMBER": tc.envValue})
+
+			res, err := env.IntInRangeStrict("WORKER_NUMBER", tc.defaultValue, 1, 64)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %d, got: %d", tc.expRes, res)
+			}
+
+			expRes := tc.expRes
+			if tc.expErr != nil {
+				expRes = tc.defaultValue
+			}
+			if res := env.IntInRange("WORKER_NUMBER", tc.defaultValue, 1, 64); res != expRes {
+				t.Errorf("expected value of ordinary method: %d, got: %d", expRes, res)
+			}
+		})
+	}
+}
+
+func TestDurationInRangeStrict(t *testing.T) {
+	env := NewEnv(MapSource{"TIMEOUT": "2m", "INTERVAL": "30s"})
+
+	_, err := env.DurationInRangeStrict("TIMEOUT", time.Second, time.Second, time.Minute)
+	expErr := errors.New(`defenv: TIMEOUT="2m" is out of range [1s, 1m0s]`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+
+	if res := env.DurationInRange("INTERVAL", time.Second, time.Second, time.Minute); res != 30*time.Second {
+		t.Errorf("expected value: %s, got: %s", 30*time.Second, res)
+	}
+	if res := env.DurationInRange("TIMEOUT", time.Second, time.Second, time.Minute); res != time.Second {
+		t.Errorf("expected value: %s, got: %s", time.Second, res)
+	}
+}
+
+func TestPositiveAndNonNegative(t *testing.T) {
+	env := NewEnv(MapSource{"ZERO": "0", "NEGATIVE": "-1", "ONE": "1", "NO_TIMEOUT": "0s"})
+
+	for _, tc := range []struct {
+		name   string
+		get    func() (interface{}, error)
+		expRes interface{}
+		expErr error
+	}{
+		{
+			name:   "positive int then value is 1",
+			get:    func() (interface{}, error) { return env.PositiveIntStrict("ONE", 8) },
+			expRes: 1,
+		},
+		{
+			name:   "positive int then value is 0",
+			get:    func() (interface{}, error) { return env.PositiveIntStrict("ZERO", 8) },
+			expRes: 0,
+			expErr: errors.New(`defenv: ZERO="0" is not positive`),
+		},
+		{
+			name:   "non-negative int then value is 0",
+			get:    func() (interface{}, error) { return env.NonNegativeIntStrict("ZERO", 8) },
+			expRes: 0,
+		},
+		{
+			name:   "non-negative int then value is -1",
+			get:    func() (interface{}, error) { return env.NonNegativeIntStrict("NEGATIVE", 8) },
+			expRes: 0,
+			expErr: errors.New(`defenv: NEGATIVE="-1" is negative`),
+		},
+		{
+			name:   "positive duration then value is 0s",
+			get:    func() (interface{}, error) { return env.PositiveDurationStrict("NO_TIMEOUT", time.Second) },
+			expRes: time.Duration(0),
+			expErr: errors.New(`defenv: NO_TIMEOUT="0s" is not positive`),
+		},
+		{
+			name:   "positive duration then value is absent",
+			get:    func() (interface{}, error) { return env.PositiveDurationStrict("ABSENT", time.Second) },
+			expRes: time.Second,
+		},
+		{
+			name:   "default value of ordinary method then value is 0",
+			get:    func() (interface{}, error) { return env.PositiveInt("ZERO", 8), nil },
+			expRes: 8,
+		},
+		{
+			name:   "default value of ordinary method then value is negative",
+			get:    func() (interface{}, error) { return env.NonNegativeInt("NEGATIVE", 8), nil },
+			expRes: 8,
+		},
+		{
+			name:   "default value of ordinary method then duration is 0s",
+			get:    func() (interface{}, error) { return env.PositiveDuration("NO_TIMEOUT", time.Second), nil },
+			expRes: time.Second,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.get()
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %v, got: %v", tc.expRes, res)
+			}
+		})
+	}
+}
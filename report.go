@@ -0,0 +1,140 @@
+package defenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Status is a result of reading a variable
+type Status string
+
+// Statuses of variables in a Report
+const (
+	StatusOK      Status = "ok"      // the variable is present and valid
+	StatusDefault Status = "default" // the variable is absent and the default value is used
+	StatusInvalid Status = "invalid" // the variable can not be read or parsed
+)
+
+// ReportEntry describes the result of reading a variable
+type ReportEntry struct {
+	Var    string
+	Status Status
+	Source string // description of the source, empty for defaults
+	Value  string // formatted value, redacted for sensitive variables
+	Err    error
+}
+
+// Fields returns the entry as key-value pairs for structured loggers,
+// e.g. logger.Info("config", entry.Fields()...)
+func (r ReportEntry) Fields() []interface{} {
+	fields := []interface{}{"var", r.Var, "status", string(r.Status), "source", r.Source, "value", r.Value}
+	if r.Err != nil {
+		fields = append(fields, "error", r.Err.Error())
+	}
+
+	return fields
+}
+
+// Report describes results of reading variables, sorted by name
+type Report []ReportEntry
+
+// ReportAll re-runs strict parsing of every environment variable read so far
+// through the package level functions and returns the results
+func ReportAll() Report {
+	return std.ReportAll()
+}
+
+// ReportAll re-runs strict parsing of every variable read so far through
+// the Env or its copies and returns the results, see ValidateAll
+func (e *Env) ReportAll() Report {
+	return e.report(e.Schema())
+}
+
+// report runs checks of schema in order of variable names
+// and describes their results
+func (e *Env) report(schema Schema) Report {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dry := e.validating()
+	report := make(Report, 0, len(names))
+	for _, name := range names {
+		err := schema[name](dry)
+		entry := dry.reads.entry(name)
+
+		status := StatusOK
+		switch {
+		case err != nil:
+			status = StatusInvalid
+		case entry.defaulted:
+			status = StatusDefault
+		}
+
+		report = append(report, ReportEntry{Var: name, Status: status, Source: entry.source, Value: entry.format(name), Err: err})
+	}
+
+	return report
+}
+
+// Err returns errors of the report joined, one per line,
+// or nil if all variables are valid
+func (r Report) Err() error {
+	var errs multiError
+	for _, entry := range r {
+		if entry.Err != nil {
+			errs = append(errs, entry.Err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// WriteTable writes the report as a table with columns
+// VAR, STATUS, SOURCE, VALUE and ERROR
+func (r Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VAR\tSTATUS\tSOURCE\tVALUE\tERROR")
+	for _, entry := range r {
+		var msg string
+		if entry.Err != nil {
+			msg = strings.Replace(entry.Err.Error(), "\n", " ", -1)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", entry.Var, entry.Status, entry.Source, entry.Value, msg)
+	}
+
+	return tw.Flush()
+}
+
+// MarshalJSON returns the report as a JSON array of objects, e.g.
+//
+// [{"var":"PORT","status":"invalid","source":"os","value":"\"abc\"","error":"..."}]
+func (r Report) MarshalJSON() ([]byte, error) {
+	type entry struct {
+		Var    string `json:"var"`
+		Status Status `json:"status"`
+		Source string `json:"source,omitempty"`
+		Value  string `json:"value"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	entries := make([]entry, len(r))
+	for i, e := range r {
+		entries[i] = entry{Var: e.Var, Status: e.Status, Source: e.Source, Value: e.Value}
+		if e.Err != nil {
+			entries[i].Error = e.Err.Error()
+		}
+	}
+
+	return json.Marshal(entries)
+}
@@ -0,0 +1,67 @@
+package defenv
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEnvReportAll(t *testing.T) {
+	defer resetSensitive()
+
+	MarkSensitive("DB_PASSWORD")
+
+	env := NewEnv(MapSource{"PORT": "abc", "TIMEOUT": "5s", "DB_PASSWORD": "secret"})
+	env.Int("PORT", 8080)
+	env.Duration("TIMEOUT", time.Second)
+	env.String("DB_PASSWORD", "")
+	env.String("HOST", "localhost")
+
+	report := env.ReportAll()
+	expReport := Report{
+		{Var: "DB_PASSWORD", Status: StatusOK, Source: "defenv.MapSource", Value: "[REDACTED]"},
+		{Var: "HOST", Status: StatusDefault, Value: `"localhost"`},
+		{Var: "PORT", Status: StatusInvalid, Source: "defenv.MapSource", Value: `"abc"`,
+			Err: &ParseError{Var: "PORT", Raw: "abc", Type: "int", Err: report[2].Err.(*ParseError).Err}},
+		{Var: "TIMEOUT", Status: StatusOK, Source: "defenv.MapSource", Value: `"5s"`},
+	}
+	if !reflect.DeepEqual(report, expReport) {
+		t.Fatalf("expected report: %v, got: %v", expReport, report)
+	}
+
+	if fmt.Sprint(report.Err()) != fmt.Sprint(env.ValidateAll()) {
+		t.Errorf("expected error: %v, got: %v", env.ValidateAll(), report.Err())
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteTable(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expTable := `VAR          STATUS   SOURCE            VALUE        ERROR
+DB_PASSWORD  ok       defenv.MapSource  [REDACTED]   
+HOST         default                    "localhost"  
+PORT         invalid  defenv.MapSource  "abc"        defenv: parse PORT="abc" as int: invalid syntax
+TIMEOUT      ok       defenv.MapSource  "5s"         
+`
+	if buf.String() != expTable {
+		t.Errorf("expected table:\n%s\ngot:\n%s", expTable, buf.String())
+	}
+
+	data, err := report[1:3].MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expJSON := `[{"var":"HOST","status":"default","value":"\"localhost\""},` +
+		`{"var":"PORT","status":"invalid","source":"defenv.MapSource","value":"\"abc\"","error":"defenv: parse PORT=\"abc\" as int: invalid syntax"}]`
+	if string(data) != expJSON {
+		t.Errorf("expected JSON: %s, got: %s", expJSON, data)
+	}
+
+	expFields := []interface{}{"var", "PORT", "status", "invalid", "source", "defenv.MapSource", "value", `"abc"`,
+		"error", `defenv: parse PORT="abc" as int: invalid syntax`}
+	if fields := report[2].Fields(); !reflect.DeepEqual(fields, expFields) {
+		t.Errorf("expected fields: %v, got: %v", expFields, fields)
+	}
+}
@@ -0,0 +1,109 @@
+package defenv
+
+// Schema is a set of checks of variables keyed by variable name.
+// A check reads the variable from env with a strict getter
+// and returns its error, if any
+type Schema map[string]func(env *Env) error
+
+// Validate runs checks of schema against the process environment
+// and returns all errors joined, one per line
+func Validate(schema Schema) error {
+	return std.Validate(schema)
+}
+
+// Validate runs checks of schema against the Env in order of variable names
+// and returns all errors joined, one per line. Errors wrapped by
+// the joined error can be inspected with errors.Is and errors.As
+func (e *Env) Validate(schema Schema) error {
+	return e.report(schema).Err()
+}
+
+// Schema returns checks of all variables read so far
+// through the Env or its copies. A check repeats the read with
+// the same options and default value using a strict getter,
+// so the schema can validate the configuration without constructing it
+func (e *Env) Schema() Schema {
+	e.reads.mu.Lock()
+	defer e.reads.mu.Unlock()
+
+	schema := make(Schema, len(e.reads.checks))
+	for name, check := range e.reads.checks {
+		schema[name] = check
+	}
+
+	return schema
+}
+
+// registered reports whether a check of the first variable of names
+// has been recorded for Schema. Callers check it before creating the check,
+// so repeated reads do not allocate
+func (e *Env) registered(names []string) bool {
+	if e.reads == nil || len(names) == 0 {
+		return true
+	}
+
+	e.reads.mu.RLock()
+	_, ok := e.reads.checks[e.prefix+names[0]]
+	e.reads.mu.RUnlock()
+
+	return ok
+}
+
+// register records check of the first variable of names for Schema.
+// The check reads the variable with the settings of the Env, such as
+// the prefix and the trimming, and the sources of the validated Env
+func (e *Env) register(names []string, check func(env *Env) error) {
+	reading := *e
+	e.reads.mu.Lock()
+	e.reads.checks[e.prefix+names[0]] = func(env *Env) error {
+		c := reading
+		c.source = env.source
+		c.strict = env.strict
+		c.onError = env.onError
+		c.onRead = env.onRead
+		c.onFail = env.onFail
+		c.reads = env.reads
+		c.renames = env.renames
+		c.conflicts = env.conflicts
+		c.cache = env.cache
+		c.dryRun = env.dryRun
+		return check(&c)
+	}
+	e.reads.mu.Unlock()
+}
+
+// validating returns a copy of the Env validating the configuration:
+// reads through the copy are not visible in hooks, metrics, the debug trace,
+// the read log or conflicts of the Env, and bypass its cache
+func (e *Env) validating() *Env {
+	c := *e
+	c.dryRun = true
+	c.strict = false
+	c.onError = nil
+	c.onRead = nil
+	c.onFail = nil
+	c.reads = newReadLog()
+	c.conflicts = nil
+	c.cache = nil
+	return &c
+}
+
+// detach returns copies of names and opts, so checks registered for Schema
+// do not make slices of callers escape to the heap
+func detach(names []string, opts []Option) ([]string, []Option) {
+	return append([]string(nil), names...), append([]Option(nil), opts...)
+}
+
+// ValidateAll re-runs strict parsing of every environment variable read so far
+// through the package level functions and returns all errors joined
+func ValidateAll() error {
+	return std.ValidateAll()
+}
+
+// ValidateAll re-runs strict parsing of every variable read so far
+// through the Env or its copies and returns all errors joined,
+// so main can have a single fail-fast gate after the configuration is read.
+// The re-reads are not visible in hooks, metrics, the debug trace or the read log
+func (e *Env) ValidateAll() error {
+	return e.ReportAll().Err()
+}
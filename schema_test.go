@@ -0,0 +1,145 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEnvValidate(t *testing.T) {
+	schema := Schema{
+		"PORT": func(env *Env) error {
+			_, err := env.IntStrict("PORT", 8080, Min(1))
+			return err
+		},
+		"TIMEOUT": func(env *Env) error {
+			_, err := env.DurationStrict("TIMEOUT", time.Second)
+			return err
+		},
+	}
+
+	tt := []struct {
+		name   string
+		source MapSource
+		expErr error
+	}{
+		{
+			name:   "all variables are valid then no error",
+			source: MapSource{"PORT": "80", "TIMEOUT": "5s"},
+		},
+		{
+			name:   "variables are absent then no error",
+			source: MapSource{},
+		},
+		{
+			name:   "variables are invalid then all errors",
+			source: MapSource{"PORT": "0", "TIMEOUT": "5"},
+			expErr: errors.New("defenv: PORT=\"0\" is less than minimum 1\n" +
+				"defenv: parse TIMEOUT=\"5\" as time.Duration: time: missing unit in duration \"5\""),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewEnv(tc.source).Validate(schema)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestEnvSchema(t *testing.T) {
+	src := MapSource{"APP_PORT": "80", "TIMEOUT": "5s", "CMD": "run"}
+	env := NewEnv(src)
+
+	env.WithPrefix("APP_").Int("PORT", 8080, Min(1))
+	env.Duration("TIMEOUT", time.Second)
+	env.Command("CMD", nil)
+
+	schema := env.Schema()
+	if len(schema) != 3 {
+		t.Fatalf("expected %d checks, got: %d", 3, len(schema))
+	}
+
+	if err := env.Validate(schema); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	src["APP_PORT"] = "0"
+	src["CMD_APPEND"] = "'"
+	err := env.Validate(schema)
+	expErr := errors.New("defenv: APP_PORT=\"0\" is less than minimum 1\n" +
+		"defenv: parse CMD_APPEND=\"'\" as []string: unterminated single quote")
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+
+	other := NewEnv(MapSource{"APP_PORT": "abc"})
+	err = other.Validate(schema)
+	expErr = errors.New(`defenv: parse APP_PORT="abc" as int: invalid syntax`)
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+}
+
+func TestEnvSchemaDerivedEnv(t *testing.T) {
+	env := NewEnv(MapSource{"PORT": " 80 ", "Q": "\"5\""})
+
+	env.WithTrimSpace().Int("PORT", 8080)
+	env.WithUnquote().Int("Q", 1)
+
+	if err := env.Validate(env.Schema()); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if err := env.ValidateAll(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestEnvValidateAll(t *testing.T) {
+	env := NewEnv(MapSource{"PORT": "abc", "TIMEOUT": "5s", "RATE": "2"})
+
+	env.Int("PORT", 8080)
+	env.Duration("TIMEOUT", time.Second)
+	env.Probability("RATE", 0.5)
+	env.String("HOST", "localhost")
+
+	err := env.ValidateAll()
+	expErr := errors.New("defenv: parse PORT=\"abc\" as int: invalid syntax\n" +
+		"defenv: RATE=\"2\" is out of range [0, 1]")
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+	if err := NewEnv(MapSource{}).ValidateAll(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestEnvValidateAllDryRun(t *testing.T) {
+	var reads int
+	m := NewMetrics()
+	env := NewEnv(MapSource{"PORT": "abc", "TIMEOUT": "5s"}).
+		OnRead(func(name, raw string, used Source, defaulted bool) { reads++ }).
+		WithMetrics(m)
+
+	env.Int("PORT", 8080)
+	env.Duration("TIMEOUT", time.Second)
+	env.String("HOST", "localhost")
+
+	before := env.Accessed()
+	if err := env.ValidateAll(); err == nil {
+		t.Fatal("expected error, got: <nil>")
+	}
+
+	if reads != 3 {
+		t.Errorf("expected %d reads, got: %d", 3, reads)
+	}
+	if m.reads["PORT"] != 1 || m.errors["PORT"] != 1 {
+		t.Errorf("expected PORT counted once, got: %d reads, %d errors", m.reads["PORT"], m.errors["PORT"])
+	}
+	if after := env.Accessed(); fmt.Sprint(after) != fmt.Sprint(before) {
+		t.Errorf("expected accessed: %v, got: %v", before, after)
+	}
+}
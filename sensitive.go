@@ -0,0 +1,66 @@
+package defenv
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// redacted replaces values of sensitive variables
+const redacted = "[REDACTED]"
+
+var sensitive struct {
+	sync.RWMutex
+	names    map[string]bool
+	patterns []string
+}
+
+// MarkSensitive marks variables as sensitive, so errors, logs and dumps
+// produced by the package mask their values. A name may be a pattern
+// with * matching any sequence of characters, e.g. "*_TOKEN" or "*PASSWORD*".
+// Other variables are never masked
+func MarkSensitive(names ...string) {
+	sensitive.Lock()
+	defer sensitive.Unlock()
+
+	if sensitive.names == nil {
+		sensitive.names = make(map[string]bool)
+	}
+
+	for _, name := range names {
+		if strings.ContainsAny(name, "*?[") {
+			sensitive.patterns = append(sensitive.patterns, name)
+		} else {
+			sensitive.names[name] = true
+		}
+	}
+}
+
+// IsSensitive reports whether variable named name is marked as sensitive
+func IsSensitive(name string) bool {
+	sensitive.RLock()
+	defer sensitive.RUnlock()
+
+	if sensitive.names[name] {
+		return true
+	}
+
+	for _, pattern := range sensitive.patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quoteValue returns quoted raw value of variable named name
+// or a placeholder if the variable is sensitive
+func quoteValue(name, raw string) string {
+	if IsSensitive(name) {
+		return redacted
+	}
+
+	return strconv.Quote(raw)
+}
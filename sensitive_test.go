@@ -0,0 +1,142 @@
+package defenv
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func resetSensitive() {
+	sensitive.Lock()
+	sensitive.names = nil
+	sensitive.patterns = nil
+	sensitive.Unlock()
+}
+
+func TestIsSensitive(t *testing.T) {
+	defer resetSensitive()
+
+	MarkSensitive("DB_PASSWORD", "API_KEY", "*_TOKEN")
+
+	for _, tc := range []struct {
+		name   string
+		expRes bool
+	}{
+		{name: "DB_PASSWORD", expRes: true},
+		{name: "API_KEY", expRes: true},
+		{name: "GITHUB_TOKEN", expRes: true},
+		{name: "_TOKEN", expRes: true},
+		{name: "TOKEN", expRes: false},
+		{name: "DB_PASSWORD_FILE", expRes: false},
+		{name: "PORT", expRes: false},
+	} {
+		if res := IsSensitive(tc.name); res != tc.expRes {
+			t.Errorf("expected IsSensitive(%q): %t, got: %t", tc.name, tc.expRes, res)
+		}
+	}
+}
+
+func TestSensitiveErrors(t *testing.T) {
+	defer resetSensitive()
+
+	MarkSensitive("SECRET_*")
+
+	env := NewEnv(MapSource{
+		"SECRET_NUMBER":   "s3cr3t",
+		"SECRET_TIMEOUT":  "s3cr3t",
+		"SECRET_WORKERS":  "100",
+		"SECRET_CMD":      `run "s3cr3t`,
+		"VISIBLE_NUMBER":  "abc",
+		"VISIBLE_WORKERS": "100",
+		"API_TOKEN":       "s3cr3t",
+	})
+
+	for _, tc := range []struct {
+		name   string
+		get    func() error
+		expErr string
+	}{
+		{
+			name: "int",
+			get: func() error {
+				_, err := env.IntStrict("SECRET_NUMBER", 0)
+				return err
+			},
+			expErr: `defenv: parse SECRET_NUMBER=[REDACTED] as int: invalid syntax`,
+		},
+		{
+			name: "duration",
+			get: func() error {
+				_, err := env.DurationStrict("SECRET_TIMEOUT", time.Second)
+				return err
+			},
+			expErr: `defenv: parse SECRET_TIMEOUT=[REDACTED] as time.Duration: time: invalid duration "[REDACTED]"`,
+		},
+		{
+			name: "range",
+			get: func() error {
+				_, err := env.IntInRangeStrict("SECRET_WORKERS", 8, 1, 64)
+				return err
+			},
+			expErr: `defenv: SECRET_WORKERS=[REDACTED] is out of range [1, 64]`,
+		},
+		{
+			name: "command",
+			get: func() error {
+				_, err := env.CommandStrict("SECRET_CMD", nil)
+				return err
+			},
+			expErr: `defenv: parse SECRET_CMD=[REDACTED] as []string: unterminated double quote`,
+		},
+		{
+			name: "not marked name containing TOKEN",
+			get: func() error {
+				_, err := env.IntStrict("API_TOKEN", 0)
+				return err
+			},
+			expErr: `defenv: parse API_TOKEN="s3cr3t" as int: invalid syntax`,
+		},
+		{
+			name: "not sensitive int",
+			get: func() error {
+				_, err := env.IntStrict("VISIBLE_NUMBER", 0)
+				return err
+			},
+			expErr: `defenv: parse VISIBLE_NUMBER="abc" as int: invalid syntax`,
+		},
+		{
+			name: "not sensitive range",
+			get: func() error {
+				_, err := env.IntInRangeStrict("VISIBLE_WORKERS", 8, 1, 64)
+				return err
+			},
+			expErr: `defenv: VISIBLE_WORKERS="100" is out of range [1, 64]`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.get(); fmt.Sprint(err) != tc.expErr {
+				t.Errorf("expected error: %s, got: %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestSensitiveDump(t *testing.T) {
+	defer resetSensitive()
+
+	MarkSensitive("*PASSWORD*")
+
+	env := NewEnv(MapSource{"DB_PASSWORD": "s3cr3t", "PORT": "80"})
+	env.String("DB_PASSWORD", "")
+	env.Int("PORT", 8080)
+
+	var buf bytes.Buffer
+	if err := env.Dump(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "s3cr3t") || !strings.Contains(buf.String(), redacted) || !strings.Contains(buf.String(), "80") {
+		t.Errorf("expected redacted dump, got: %s", buf.String())
+	}
+}
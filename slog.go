@@ -0,0 +1,44 @@
+//go:build go1.21
+// +build go1.21
+
+package defenv
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger returns Env reading the process environment
+// and logging every read with logger, see Env.WithLogger
+func WithLogger(logger *slog.Logger) *Env {
+	return std.WithLogger(logger)
+}
+
+// WithLogger returns a copy of the Env emitting a debug record for every
+// read of a variable with its name, source, value and whether it is absent.
+// Values of variables marked by MarkSensitive are redacted.
+// A hook set by OnRead is still called
+func (e *Env) WithLogger(logger *slog.Logger) *Env {
+	prev, ctx := e.onRead, e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return e.OnRead(chainRead(prev, func(name, raw string, used Source, defaulted bool) {
+		logger.LogAttrs(ctx, slog.LevelDebug, "defenv: read variable",
+			slog.String("name", name),
+			slog.String("source", sourceName(used)),
+			slog.Bool("defaulted", defaulted),
+			slog.String("value", redact(name, raw)),
+		)
+	}))
+}
+
+// redact hides raw if the variable is sensitive
+func redact(name, raw string) string {
+	if IsSensitive(name) {
+		return redacted
+	}
+
+	return raw
+}
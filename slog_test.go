@@ -0,0 +1,66 @@
+//go:build go1.21
+// +build go1.21
+
+package defenv
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestEnvWithLogger(t *testing.T) {
+	defer resetSensitive()
+
+	MarkSensitive("DB_PASSWORD")
+
+	var (
+		buf   bytes.Buffer
+		calls int
+	)
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	env := NewEnv(MapSource{"PORT": "8080", "DB_PASSWORD": "secret"}, OS).
+		OnRead(func(name, raw string, used Source, defaulted bool) { calls++ }).
+		WithLogger(logger)
+
+	env.Int("PORT", 80)
+	env.String("DB_PASSWORD", "")
+	env.Duration("DEFENV_TEST_ABSENT", 0)
+
+	expLog := `level=DEBUG msg="defenv: read variable" name=PORT source=defenv.MapSource defaulted=false value=8080
+level=DEBUG msg="defenv: read variable" name=DB_PASSWORD source=defenv.MapSource defaulted=false value=[REDACTED]
+level=DEBUG msg="defenv: read variable" name=DEFENV_TEST_ABSENT source="" defaulted=true value=""
+`
+	if buf.String() != expLog {
+		t.Errorf("expected log:\n%s\ngot:\n%s", expLog, buf.String())
+	}
+	if calls != 3 {
+		t.Errorf("expected %d calls of OnRead hook, got: %d", 3, calls)
+	}
+}
+
+func TestSourceName(t *testing.T) {
+	for _, tc := range []struct {
+		src     Source
+		expName string
+	}{
+		{src: nil, expName: ""},
+		{src: OS, expName: "os"},
+		{src: MapSource{}, expName: "defenv.MapSource"},
+		{src: KVDirSource("/etc/config"), expName: "defenv.KVDirSource"},
+	} {
+		if name := sourceName(tc.src); name != tc.expName {
+			t.Errorf("expected name: %q, got: %q", tc.expName, name)
+		}
+	}
+}
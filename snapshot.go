@@ -0,0 +1,128 @@
+package defenv
+
+import (
+	"os"
+	"sort"
+)
+
+// Snapshot is a set of variables and their values at a point in time.
+// Snapshot is a Source, so an Env can read a consistent view of variables
+type Snapshot map[string]string
+
+// Lookup returns value of variable named name and reports whether it is present
+func (s Snapshot) Lookup(name string) (string, bool) {
+	val, ok := s[name]
+	return val, ok
+}
+
+func (s Snapshot) String() string {
+	return "snapshot"
+}
+
+// Names returns names of all variables of the snapshot
+func (s Snapshot) Names() ([]string, error) {
+	return MapSource(s).Names()
+}
+
+// TakeSnapshot captures the entire process environment at a point in time.
+// An Env reading the snapshot sees a consistent view of variables, so
+// a multi-step initialization is not affected by concurrent changes
+// of the process environment, e.g. by tests or plugins. The environment
+// is read once and lookups are served from a map, which is faster than
+// reading the process environment in tight loops
+func TakeSnapshot() Snapshot {
+	return Snapshot(parseEnviron(os.Environ()))
+}
+
+// Snapshot returns current values of all variables read so far through
+// the Env or its copies. Absent variables are not included
+func (e *Env) Snapshot() Snapshot {
+	names, _ := e.reads.snapshot()
+
+	root := *e
+	root.prefix = ""
+
+	s := make(Snapshot, len(names))
+	for _, name := range names {
+		if val, ok, _, err := root.lookup(name); ok && err == nil {
+			s[name] = val
+		}
+	}
+
+	return s
+}
+
+// ChangeKind describes how a variable has changed
+type ChangeKind int
+
+// Kinds of changes
+const (
+	Added ChangeKind = iota + 1
+	Removed
+	Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+
+	return "unknown"
+}
+
+// Change is a change of a variable between two snapshots
+type Change struct {
+	Name string
+	Kind ChangeKind
+	Old  string // value before the change, empty if the variable is added
+	New  string // value after the change, empty if the variable is removed
+}
+
+// String returns a description of the change like PORT: "80" -> "8080".
+// Values of sensitive variables are redacted
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return c.Name + ": added " + quoteValue(c.Name, c.New)
+	case Removed:
+		return c.Name + ": removed " + quoteValue(c.Name, c.Old)
+	}
+
+	return c.Name + ": " + quoteValue(c.Name, c.Old) + " -> " + quoteValue(c.Name, c.New)
+}
+
+// Diff returns variables added, removed or modified in newSnapshot
+// compared to oldSnapshot, sorted by name
+func Diff(oldSnapshot, newSnapshot Snapshot) []Change {
+	var changes []Change
+	for name, oldVal := range oldSnapshot {
+		newVal, ok := newSnapshot[name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Name: name, Kind: Removed, Old: oldVal})
+		case newVal != oldVal:
+			changes = append(changes, Change{Name: name, Kind: Modified, Old: oldVal, New: newVal})
+		}
+	}
+
+	for name, newVal := range newSnapshot {
+		if _, ok := oldSnapshot[name]; !ok {
+			changes = append(changes, Change{Name: name, Kind: Added, New: newVal})
+		}
+	}
+
+	sort.Sort(byName(changes))
+
+	return changes
+}
+
+type byName []Change
+
+func (c byName) Len() int           { return len(c) }
+func (c byName) Less(i, j int) bool { return c[i].Name < c[j].Name }
+func (c byName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
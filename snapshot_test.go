@@ -0,0 +1,136 @@
+package defenv
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	defer resetSensitive()
+
+	MarkSensitive("DB_PASSWORD")
+
+	oldSnapshot := Snapshot{"PORT": "80", "HOST": "localhost", "DEBUG": "true", "DB_PASSWORD": "old"}
+	newSnapshot := Snapshot{"PORT": "8080", "HOST": "localhost", "TIMEOUT": "5s", "DB_PASSWORD": "new"}
+
+	changes := Diff(oldSnapshot, newSnapshot)
+	expChanges := []Change{
+		{Name: "DB_PASSWORD", Kind: Modified, Old: "old", New: "new"},
+		{Name: "DEBUG", Kind: Removed, Old: "true"},
+		{Name: "PORT", Kind: Modified, Old: "80", New: "8080"},
+		{Name: "TIMEOUT", Kind: Added, New: "5s"},
+	}
+	if !reflect.DeepEqual(changes, expChanges) {
+		t.Fatalf("expected changes: %v, got: %v", expChanges, changes)
+	}
+
+	expStr := `[DB_PASSWORD: [REDACTED] -> [REDACTED] DEBUG: removed "true" PORT: "80" -> "8080" TIMEOUT: added "5s"]`
+	if fmt.Sprint(changes) != expStr {
+		t.Errorf("expected description: %s, got: %s", expStr, fmt.Sprint(changes))
+	}
+
+	if changes := Diff(oldSnapshot, oldSnapshot); len(changes) != 0 {
+		t.Errorf("expected no changes, got: %v", changes)
+	}
+}
+
+func TestEnvSnapshot(t *testing.T) {
+	src := MapSource{"APP_PORT": "80", "APP_HOST": "localhost", "OTHER": "x"}
+	env := NewEnv(src)
+
+	env.WithPrefix("APP_").Int("PORT", 0)
+	env.WithPrefix("APP_").String("HOST", "")
+	env.String("ABSENT", "")
+
+	oldSnapshot := env.Snapshot()
+	expSnapshot := Snapshot{"APP_PORT": "80", "APP_HOST": "localhost"}
+	if !reflect.DeepEqual(oldSnapshot, expSnapshot) {
+		t.Fatalf("expected snapshot: %v, got: %v", expSnapshot, oldSnapshot)
+	}
+
+	src["APP_PORT"] = "8080"
+	src["ABSENT"] = "present"
+	delete(src, "APP_HOST")
+
+	changes := Diff(oldSnapshot, env.Snapshot())
+	expChanges := []Change{
+		{Name: "ABSENT", Kind: Added, New: "present"},
+		{Name: "APP_HOST", Kind: Removed, Old: "localhost"},
+		{Name: "APP_PORT", Kind: Modified, Old: "80", New: "8080"},
+	}
+	if !reflect.DeepEqual(changes, expChanges) {
+		t.Errorf("expected changes: %v, got: %v", expChanges, changes)
+	}
+
+	if val, ok := oldSnapshot.Lookup("APP_PORT"); !ok || val != "80" {
+		t.Errorf("expected snapshot value: %q, got: %q (%t)", "80", val, ok)
+	}
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	if err := os.Setenv("VALUE", "before"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("VALUE")
+
+	env := NewEnv(TakeSnapshot())
+
+	if err := os.Setenv("VALUE", "after"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("VALUE_NEW", "new"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("VALUE_NEW")
+
+	if res := env.String("VALUE", ""); res != "before" {
+		t.Errorf("expected result: %q, got: %q", "before", res)
+	}
+	if res := env.String("VALUE_NEW", "default"); res != "default" {
+		t.Errorf("expected result: %q, got: %q", "default", res)
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	if err := os.Setenv("VALUE", "8"); err != nil {
+		b.Fatal(err)
+	}
+	defer os.Unsetenv("VALUE")
+
+	for _, bc := range []struct {
+		name string
+		env  *Env
+	}{
+		{name: "os", env: NewEnv(OS)},
+		{name: "snapshot", env: NewEnv(TakeSnapshot())},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bc.env.String("VALUE", "")
+			}
+		})
+	}
+}
+
+func BenchmarkSourceLookup(b *testing.B) {
+	if err := os.Setenv("VALUE", "8"); err != nil {
+		b.Fatal(err)
+	}
+	defer os.Unsetenv("VALUE")
+
+	for _, bc := range []struct {
+		name string
+		src  Source
+	}{
+		{name: "os", src: OS},
+		{name: "snapshot", src: TakeSnapshot()},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bc.src.Lookup("VALUE")
+			}
+		})
+	}
+}
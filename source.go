@@ -0,0 +1,181 @@
+package defenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Source provides values of variables
+type Source interface {
+	// Lookup returns value of variable named name and reports whether it is present
+	Lookup(name string) (string, bool)
+}
+
+// SourceContext is a Source whose lookups may block, e.g. because they
+// reach a remote backend. LookupContext honours deadline and cancellation
+// of ctx and returns an error if the value can not be retrieved
+type SourceContext interface {
+	Source
+	LookupContext(ctx context.Context, name string) (string, bool, error)
+}
+
+// Enumerator is implemented by sources able to list names of their
+// variables. ErrorOnUnknown, Values and suggestions of similar names
+// in errors only see variables of sources implementing it
+type Enumerator interface {
+	// Names returns names of all variables of the source in any order
+	Names() ([]string, error)
+}
+
+// OS is a Source of the process environment
+var OS Source = osSource{}
+
+type osSource struct{}
+
+func (osSource) Lookup(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+func (osSource) String() string {
+	return "os"
+}
+
+func (osSource) Names() ([]string, error) {
+	environ := os.Environ()
+	names := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			names = append(names, kv[:i])
+		}
+	}
+
+	return names, nil
+}
+
+// MapSource is a Source backed by a map of variable names to values
+type MapSource map[string]string
+
+// Lookup returns value of variable named name and reports whether it is present
+func (s MapSource) Lookup(name string) (string, bool) {
+	val, ok := s[name]
+	return val, ok
+}
+
+// Names returns names of all variables of the map
+func (s MapSource) Names() ([]string, error) {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// LookupFunc adapts a function with the signature of os.LookupEnv to Source
+type LookupFunc func(name string) (string, bool)
+
+// Lookup returns f(name)
+func (f LookupFunc) Lookup(name string) (string, bool) {
+	return f(name)
+}
+
+// Layered returns a Source that consults sources in order and returns
+// the value from the first one containing a variable. The returned Source
+// implements SourceContext and passes the context to the sources that
+// implement it too
+func Layered(sources ...Source) Source {
+	return layered(sources)
+}
+
+type layered []Source
+
+func (l layered) Lookup(name string) (string, bool) {
+	val, ok, _, _ := l.lookup(nil, name)
+	return val, ok
+}
+
+// Names returns names of variables of all sources implementing Enumerator,
+// a name present in several sources is repeated
+func (l layered) Names() ([]string, error) {
+	var names []string
+	for _, src := range l {
+		if src, ok := src.(Enumerator); ok {
+			srcNames, err := src.Names()
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, srcNames...)
+		}
+	}
+
+	return names, nil
+}
+
+func (l layered) LookupContext(ctx context.Context, name string) (string, bool, error) {
+	val, ok, _, err := l.lookup(ctx, name)
+	return val, ok, err
+}
+
+// lookup returns value of variable named name and the source containing it.
+// If ctx is not nil, it is passed to sources implementing SourceContext
+func (l layered) lookup(ctx context.Context, name string) (string, bool, Source, error) {
+	for _, src := range l {
+		if nested, ok := src.(layered); ok {
+			val, ok, used, err := nested.lookup(ctx, name)
+			if err != nil || ok {
+				return val, ok, used, err
+			}
+			continue
+		}
+
+		if ctx != nil {
+			if err := ctx.Err(); err != nil {
+				return "", false, nil, err
+			}
+
+			if srcCtx, ok := src.(SourceContext); ok {
+				val, ok, err := srcCtx.LookupContext(ctx, name)
+				if err != nil {
+					return "", false, nil, err
+				}
+				if ok {
+					return val, true, src, nil
+				}
+				continue
+			}
+		}
+
+		if val, ok := src.Lookup(name); ok {
+			return val, true, src, nil
+		}
+	}
+
+	return "", false, nil, nil
+}
+
+// fileSource is reported as the source of a value read from a file
+// named by name_FILE variable
+type fileSource string
+
+func (s fileSource) Lookup(name string) (string, bool) {
+	return "", false
+}
+
+func (s fileSource) String() string {
+	return "file " + string(s)
+}
+
+// sourceName returns a short description of src for logs and dumps
+func sourceName(src Source) string {
+	switch src := src.(type) {
+	case nil:
+		return ""
+	case fmt.Stringer:
+		return src.String()
+	default:
+		return reflect.TypeOf(src).String()
+	}
+}
@@ -0,0 +1,162 @@
+package defenv
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMapSource(t *testing.T) {
+	src := MapSource{"VALUE": "test", "EMPTY": ""}
+
+	for _, tc := range []struct {
+		name   string
+		key    string
+		expRes string
+		expOk  bool
+	}{
+		{
+			name:   `"test" then variable is "test"`,
+			key:    "VALUE",
+			expRes: "test",
+			expOk:  true,
+		},
+		{
+			name:   `"" then variable is ""`,
+			key:    "EMPTY",
+			expRes: "",
+			expOk:  true,
+		},
+		{
+			name:  `absent then variable is not in map`,
+			key:   "ABSENT",
+			expOk: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, ok := src.Lookup(tc.key)
+			if ok != tc.expOk {
+				t.Errorf("expected presence: %t, got: %t", tc.expOk, ok)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestLayered(t *testing.T) {
+	src := Layered(
+		MapSource{"ENV": "env"},
+		Layered(MapSource{"FILE": "file", "ENV": "file"}),
+		MapSource{"REMOTE": "remote", "FILE": "remote", "ENV": "remote"},
+	)
+
+	for _, tc := range []struct {
+		name   string
+		key    string
+		expRes string
+		expOk  bool
+	}{
+		{
+			name:   `value from the first layer`,
+			key:    "ENV",
+			expRes: "env",
+			expOk:  true,
+		},
+		{
+			name:   `value from nested layer`,
+			key:    "FILE",
+			expRes: "file",
+			expOk:  true,
+		},
+		{
+			name:   `value from the last layer`,
+			key:    "REMOTE",
+			expRes: "remote",
+			expOk:  true,
+		},
+		{
+			name:  `absent then no layer contains variable`,
+			key:   "ABSENT",
+			expOk: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, ok := src.Lookup(tc.key)
+			if ok != tc.expOk {
+				t.Errorf("expected presence: %t, got: %t", tc.expOk, ok)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
+
+// ctxSource is a SourceContext failing lookups if its context is done
+type ctxSource struct {
+	MapSource
+	lookups int
+}
+
+func (s *ctxSource) LookupContext(ctx context.Context, name string) (string, bool, error) {
+	s.lookups++
+	if err := ctx.Err(); err != nil {
+		return "", false, err
+	}
+
+	val, ok := s.Lookup(name)
+	return val, ok, nil
+}
+
+func TestLayeredLookupContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, tc := range []struct {
+		name   string
+		ctx    context.Context
+		layers []Source
+		expRes string
+		expOk  bool
+		expErr error
+	}{
+		{
+			name:   `value from SourceContext layer`,
+			ctx:    context.Background(),
+			layers: []Source{MapSource{}, &ctxSource{MapSource: MapSource{"VALUE": "remote"}}},
+			expRes: "remote",
+			expOk:  true,
+		},
+		{
+			name:   `fail then context is canceled before plain layer`,
+			ctx:    canceled,
+			layers: []Source{MapSource{"VALUE": "local"}, &ctxSource{MapSource: MapSource{"VALUE": "remote"}}},
+			expErr: context.Canceled,
+		},
+		{
+			name:   `fail then context is canceled`,
+			ctx:    canceled,
+			layers: []Source{&ctxSource{MapSource: MapSource{"VALUE": "remote"}}},
+			expErr: context.Canceled,
+		},
+		{
+			name:   `absent then no layer contains variable`,
+			ctx:    context.Background(),
+			layers: []Source{&ctxSource{MapSource: MapSource{}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, ok, err := Layered(tc.layers...).(SourceContext).LookupContext(tc.ctx, "VALUE")
+			if err != tc.expErr {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if ok != tc.expOk {
+				t.Errorf("expected presence: %t, got: %t", tc.expOk, ok)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %s, got: %s", tc.expRes, res)
+			}
+		})
+	}
+}
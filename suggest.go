@@ -0,0 +1,87 @@
+package defenv
+
+import (
+	"sort"
+	"strings"
+)
+
+// suggest returns name of a present variable similar to one of names,
+// e.g. DB_PASWORD for DB_PASSWORD, or an empty string if there is none.
+// Only sources able to list their variables are scanned
+func (e *Env) suggest(names []string) string {
+	src, ok := e.source.(Enumerator)
+	if !ok {
+		return ""
+	}
+
+	candidates, err := src.Names()
+	if err != nil {
+		return ""
+	}
+	sort.Strings(candidates)
+
+	var (
+		best     string
+		bestDist = -1
+	)
+	for _, name := range names {
+		upperName := strings.ToUpper(name)
+		maxDist := len(name)/4 + 1
+		for _, candidate := range candidates {
+			if candidate == name {
+				continue
+			}
+
+			dist := editDistance(upperName, strings.ToUpper(candidate))
+			if dist <= maxDist && (bestDist < 0 || dist < bestDist) {
+				best, bestDist = candidate, dist
+			}
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the number of insertions, deletions, substitutions
+// and transpositions of adjacent bytes needed to turn a into b
+func editDistance(a, b string) int {
+	var (
+		prev2 = make([]int, len(b)+1)
+		prev  = make([]int, len(b)+1)
+		cur   = make([]int, len(b)+1)
+	)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && prev2[j-2]+1 < cur[j] {
+				cur[j] = prev2[j-2] + 1
+			}
+		}
+
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+
+	return a
+}
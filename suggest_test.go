@@ -0,0 +1,65 @@
+package defenv
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	for _, tc := range []struct {
+		a, b    string
+		expDist int
+	}{
+		{a: "", b: "", expDist: 0},
+		{a: "PORT", b: "PORT", expDist: 0},
+		{a: "PORT", b: "", expDist: 4},
+		{a: "PORT", b: "PROT", expDist: 1},
+		{a: "DB_PASSWORD", b: "DB_PASWORD", expDist: 1},
+		{a: "DB_HOST", b: "DB_PORT", expDist: 2},
+		{a: "KITTEN", b: "SITTING", expDist: 3},
+	} {
+		t.Run(fmt.Sprintf("%s to %s", tc.a, tc.b), func(t *testing.T) {
+			if dist := editDistance(tc.a, tc.b); dist != tc.expDist {
+				t.Errorf("expected distance: %d, got: %d", tc.expDist, dist)
+			}
+		})
+	}
+}
+
+func TestNotSetSuggestion(t *testing.T) {
+	env := NewEnv(MapSource{"DB_PASWORD": "secret", "DB_HOST": "db", "db_user": "admin"}, MapSource{"PROT": "80"})
+
+	for _, tc := range []struct {
+		name   string
+		key    string
+		expErr string
+	}{
+		{
+			name:   "suggest then a letter is missing",
+			key:    "DB_PASSWORD",
+			expErr: "defenv: variable DB_PASSWORD is not set, did you mean DB_PASWORD?",
+		},
+		{
+			name:   "suggest then letters are transposed in another source",
+			key:    "PORT",
+			expErr: "defenv: variable PORT is not set, did you mean PROT?",
+		},
+		{
+			name:   "suggest then case differs",
+			key:    "DB_USER",
+			expErr: "defenv: variable DB_USER is not set, did you mean db_user?",
+		},
+		{
+			name:   "no suggestion then nothing is similar",
+			key:    "TIMEOUT",
+			expErr: "defenv: variable TIMEOUT is not set",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := env.IntStrict(tc.key, 0, Required())
+			if fmt.Sprint(err) != tc.expErr {
+				t.Errorf("expected error: %s, got: %v", tc.expErr, err)
+			}
+		})
+	}
+}
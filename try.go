@@ -0,0 +1,40 @@
+package defenv
+
+// Try calls fn and returns the error if fn panics because an ordinary getter
+// of an Env in strict mode fails. Library code can read its configuration
+// with an Env in strict mode during initialization, and the host application
+// gets a single error instead of a panic. Panics of other origin are propagated
+func Try(fn func()) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		if f, ok := r.(*failure); ok {
+			err = f.err
+			return
+		}
+
+		panic(r)
+	}()
+
+	fn()
+
+	return nil
+}
+
+// failure is the panic value of an Env in strict mode, so Try recovers
+// every error of the package and only them. It is an error wrapping the
+// error of the getter for code recovering the panic itself
+type failure struct {
+	err error
+}
+
+func (f *failure) Error() string {
+	return f.err.Error()
+}
+
+func (f *failure) Unwrap() error {
+	return f.err
+}
@@ -0,0 +1,70 @@
+package defenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTry(t *testing.T) {
+	env := NewEnv(MapSource{"PORT": "abc", "HOST": "localhost"}).Strict(true)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tt := []struct {
+		name   string
+		fn     func()
+		expErr error
+	}{
+		{
+			name: "getters succeed then no error",
+			fn: func() {
+				env.String("HOST", "")
+				env.Int("ABSENT", 1)
+			},
+		},
+		{
+			name: "getter fails then its error",
+			fn: func() {
+				env.Int("PORT", 0)
+				t.Error("expected getter to panic")
+			},
+			expErr: errors.New(`defenv: parse PORT="abc" as int: invalid syntax`),
+		},
+		{
+			name: "required variable is absent then its error",
+			fn: func() {
+				env.String("ABSENT", "", Required())
+			},
+			expErr: errors.New("defenv: variable ABSENT is not set"),
+		},
+		{
+			name: "source fails then its error",
+			fn: func() {
+				NewEnv(&ctxSource{MapSource: MapSource{}}).WithContext(canceled).Strict(true).Int("PORT", 0)
+			},
+			expErr: context.Canceled,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Try(tc.fn)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestTryForeignPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "foreign" {
+			t.Errorf("expected panic: %q, got: %v", "foreign", r)
+		}
+	}()
+
+	Try(func() { panic("foreign") })
+	t.Error("expected panic to be propagated")
+}
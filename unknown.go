@@ -0,0 +1,95 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknown is returned by ErrorOnUnknown if there are unknown variables,
+// use errors.Is to check for it
+var ErrUnknown = errors.New("defenv: unknown variable")
+
+// ErrorOnUnknown returns an error if the process environment contains
+// variables starting with prefix which have not been read so far.
+// Call it after the configuration is read to catch misspelled variables,
+// log the error instead of failing to only warn about them
+func ErrorOnUnknown(prefix string) error {
+	return std.ErrorOnUnknown(prefix)
+}
+
+// ErrorOnUnknown returns an error if the source contains
+// variables starting with prefix which have not been looked up so far
+// through the Env or its copies, including references expanded
+// by its Expander. NAME_FILE is known if NAME has been looked up.
+// Only sources implementing Enumerator are checked
+func (e *Env) ErrorOnUnknown(prefix string) error {
+	src, ok := e.source.(Enumerator)
+	if !ok {
+		return nil
+	}
+
+	names, err := src.Names()
+	if err != nil {
+		return fmt.Errorf("defenv: list variables: %w", err)
+	}
+
+	var (
+		unknown []string
+		seen    = make(map[string]bool)
+	)
+
+	e.reads.mu.Lock()
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if !e.reads.known(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	e.reads.mu.Unlock()
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+
+	return &unknownError{names: unknown}
+}
+
+// known reports whether variable named name has been looked up
+// or holds a path to a file with value of a looked up variable.
+// The caller must hold l.mu
+func (l *readLog) known(name string) bool {
+	if l.accessed[name] {
+		return true
+	}
+
+	return strings.HasSuffix(name, fileSuffix) && l.accessed[strings.TrimSuffix(name, fileSuffix)]
+}
+
+// unknownError reports names of unknown variables
+type unknownError struct {
+	names []string
+}
+
+func (e *unknownError) Error() string {
+	if len(e.names) == 1 {
+		return "defenv: unknown variable " + e.names[0]
+	}
+
+	return "defenv: unknown variables " + strings.Join(e.names, ", ")
+}
+
+func (e *unknownError) Unwrap() error {
+	return ErrUnknown
+}
+
+func (e *unknownError) Code() Code {
+	return CodeUnknown
+}
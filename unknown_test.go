@@ -0,0 +1,107 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvErrorOnUnknown(t *testing.T) {
+	tt := []struct {
+		name   string
+		source Source
+		read   []string
+		prefix string
+		expErr error
+	}{
+		{
+			name:   "all prefixed variables are read then no error",
+			source: MapSource{"APP_PORT": "80", "APP_HOST": "localhost", "OTHER": "x"},
+			read:   []string{"APP_PORT", "APP_HOST"},
+			prefix: "APP_",
+		},
+		{
+			name:   "prefixed variable is not read then error",
+			source: MapSource{"APP_PROT": "80", "APP_HOST": "localhost", "OTHER": "x"},
+			read:   []string{"APP_PORT", "APP_HOST"},
+			prefix: "APP_",
+			expErr: errors.New("defenv: unknown variable APP_PROT"),
+		},
+		{
+			name:   "several prefixed variables are not read then error",
+			source: Layered(MapSource{"APP_B": "1"}, MapSource{"APP_A": "1", "APP_B": "2"}),
+			prefix: "APP_",
+			expErr: errors.New("defenv: unknown variables APP_A, APP_B"),
+		},
+		{
+			name:   "source fails to list variables then error",
+			source: failingEnumerator{},
+			prefix: "APP_",
+			expErr: errors.New("defenv: list variables: permission denied"),
+		},
+		{
+			name:   "source can not list variables then no error",
+			source: LookupFunc(func(string) (string, bool) { return "1", true }),
+			prefix: "APP_",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			env := NewEnv(tc.source)
+			for _, name := range tc.read {
+				env.String(name, "")
+			}
+
+			err := env.ErrorOnUnknown(tc.prefix)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if err != nil && tc.source != (failingEnumerator{}) && !errors.Is(err, ErrUnknown) {
+				t.Errorf("expected error to wrap ErrUnknown, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestEnvErrorOnUnknownConsumed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "defenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "password")
+	if err := ioutil.WriteFile(path, []byte("s3cr3t"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := NewEnv(MapSource{
+		"APP_DB_PASSWORD_FILE": path,
+		"APP_PORT_FILE":        filepath.Join(dir, "absent"),
+		"APP_HOST":             "localhost",
+		"APP_URL":              "http://${APP_HOST}",
+		"APP_OTHER_FILE":       path,
+	})
+	fileEnv := env.WithFileFallback()
+	fileEnv.String("APP_DB_PASSWORD", "")
+	fileEnv.Int("APP_PORT", 80)
+	env.Expander().Expand(env.String("APP_URL", ""))
+
+	err = env.ErrorOnUnknown("APP_")
+	expErr := errors.New("defenv: unknown variable APP_OTHER_FILE")
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+}
+
+type failingEnumerator struct{}
+
+func (failingEnumerator) Lookup(string) (string, bool) { return "", false }
+
+func (failingEnumerator) Names() ([]string, error) {
+	return nil, errors.New("permission denied")
+}
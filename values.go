@@ -0,0 +1,58 @@
+package defenv
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Values collects all environment variables whose names start with prefix
+// into url.Values. Keys are variable names without the prefix,
+// comma-separated values are split into several values of the key
+func Values(prefix string) url.Values {
+	return std.Values(prefix)
+}
+
+// Values collects all variables whose names start with prefix
+// into url.Values. Keys are variable names without the prefix,
+// comma-separated values are split into several values of the key.
+// Only sources implementing Enumerator are listed
+func (e *Env) Values(prefix string) url.Values {
+	values := url.Values{}
+
+	src, ok := e.source.(Enumerator)
+	if !ok {
+		return values
+	}
+
+	names, err := src.Names()
+	if err != nil {
+		e.fail(fmt.Errorf("defenv: list variables: %w", err))
+		return values
+	}
+	sort.Strings(names)
+
+	full := e.prefix + prefix
+	for i, name := range names {
+		if len(name) <= len(full) || !strings.HasPrefix(name, full) || i > 0 && name == names[i-1] {
+			continue
+		}
+
+		_, val, ok, err := e.value([]string{name[len(e.prefix):]}, defValue{}, options{})
+		if err != nil {
+			e.fail(err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		key := name[len(full):]
+		for _, v := range strings.Split(val, ",") {
+			values.Add(key, v)
+		}
+	}
+
+	return values
+}
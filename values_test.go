@@ -0,0 +1,92 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		env    map[string]string
+		prefix string
+		expRes url.Values
+	}{
+		{
+			name: `variables with prefix are collected`,
+			env: map[string]string{
+				"DEFENV_TEST_HOST":  "localhost",
+				"DEFENV_TEST_TAGS":  "a,b,c",
+				"DEFENV_TEST_EMPTY": "",
+				"DEFENV_OTHER":      "x",
+			},
+			prefix: "DEFENV_TEST_",
+			expRes: url.Values{
+				"HOST":  {"localhost"},
+				"TAGS":  {"a", "b", "c"},
+				"EMPTY": {""},
+			},
+		},
+		{
+			name:   `variable equal to prefix is skipped`,
+			env:    map[string]string{"DEFENV_TEST_": "x"},
+			prefix: "DEFENV_TEST_",
+			expRes: url.Values{},
+		},
+		{
+			name:   `empty values then there are no variables with prefix`,
+			env:    map[string]string{},
+			prefix: "DEFENV_TEST_",
+			expRes: url.Values{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				for name := range tc.env {
+					if err := os.Unsetenv(name); err != nil {
+						t.Errorf("coudn't unset %s: %s", name, err)
+					}
+				}
+			}()
+
+			for name, value := range tc.env {
+				if err := os.Setenv(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res := Values(tc.prefix)
+			if !reflect.DeepEqual(res, tc.expRes) {
+				t.Errorf("expected value: %v, got: %v", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestEnvValues(t *testing.T) {
+	env := NewEnv(Layered(
+		MapSource{"APP_DB_HOST": "primary"},
+		MapSource{"APP_DB_HOST": "replica", "APP_DB_TAGS": "a,b", "APP_DB_": "x", "APP_PORT": "80"},
+	)).WithPrefix("APP_")
+
+	res := env.Values("DB_")
+	expRes := url.Values{"HOST": {"primary"}, "TAGS": {"a", "b"}}
+	if !reflect.DeepEqual(res, expRes) {
+		t.Errorf("expected value: %v, got: %v", expRes, res)
+	}
+
+	err := env.ErrorOnUnknown("APP_")
+	expErr := errors.New("defenv: unknown variables APP_DB_, APP_PORT")
+	if fmt.Sprint(err) != fmt.Sprint(expErr) {
+		t.Errorf("expected error: %v, got: %v", expErr, err)
+	}
+
+	res = NewEnv(LookupFunc(func(string) (string, bool) { return "1", true })).Values("")
+	if !reflect.DeepEqual(res, url.Values{}) {
+		t.Errorf("expected value: %v, got: %v", url.Values{}, res)
+	}
+}
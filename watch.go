@@ -0,0 +1,37 @@
+package defenv
+
+import (
+	"context"
+	"time"
+)
+
+// Watch checks environment variables read so far through the package level
+// functions every interval and calls fn with their changes, see Env.Watch
+func Watch(ctx context.Context, interval time.Duration, fn func(changes []Change)) error {
+	return std.Watch(ctx, interval, fn)
+}
+
+// Watch takes a snapshot of variables read so far through the Env or its
+// copies every interval and calls fn with changes since the previous one,
+// enabling settings tunable at runtime, e.g. a log level. Variables read
+// after Watch is called are watched too. Watch blocks until ctx is done
+// and returns its error
+func (e *Env) Watch(ctx context.Context, interval time.Duration, fn func(changes []Change)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	prev := e.Snapshot()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		cur := e.Snapshot()
+		if changes := Diff(prev, cur); len(changes) > 0 {
+			fn(changes)
+		}
+		prev = cur
+	}
+}
@@ -0,0 +1,62 @@
+package defenv
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEnvWatch(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		src    = MapSource{"LOG_LEVEL": "info"}
+		looked = make(chan struct{}, 1)
+	)
+	env := NewEnv(LookupFunc(func(name string) (string, bool) {
+		mu.Lock()
+		val, ok := src.Lookup(name)
+		mu.Unlock()
+
+		select {
+		case looked <- struct{}{}:
+		default:
+		}
+
+		return val, ok
+	}))
+	env.String("LOG_LEVEL", "warn")
+	<-looked
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changed := make(chan []Change)
+	done := make(chan error)
+	go func() {
+		done <- env.Watch(ctx, time.Millisecond, func(changes []Change) {
+			changed <- changes
+		})
+	}()
+
+	<-looked // the first snapshot is taken
+	mu.Lock()
+	src["LOG_LEVEL"] = "debug"
+	mu.Unlock()
+
+	select {
+	case changes := <-changed:
+		expChanges := []Change{{Name: "LOG_LEVEL", Kind: Modified, Old: "info", New: "debug"}}
+		if !reflect.DeepEqual(changes, expChanges) {
+			t.Errorf("expected changes: %v, got: %v", expChanges, changes)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected changes to be reported")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected error: %v, got: %v", context.Canceled, err)
+	}
+}
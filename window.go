@@ -0,0 +1,132 @@
+package defenv
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time interval such as 22:00-06:00. Start and End are
+// offsets from midnight in Location. If End is less than Start, the window
+// crosses midnight. If they are equal, the window covers the whole day
+type Window struct {
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+// Contains reports whether t falls into the window
+func (w Window) Contains(t time.Time) bool {
+	if w.Location != nil {
+		t = t.In(w.Location)
+	}
+
+	h, m, s := t.Clock()
+	offset := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+
+	switch {
+	case w.Start < w.End:
+		return offset >= w.Start && offset < w.End
+	case w.Start > w.End:
+		return offset >= w.Start || offset < w.End
+	default:
+		return true
+	}
+}
+
+// TimeWindow extracts Window value from environment variable named name
+// and returns defaultValue if it is absent or can not be parsed.
+// The value has form "22:00-06:00" optionally followed by a time zone name:
+// "22:00-06:00 Europe/Berlin". Local time zone is used if it is omitted
+func TimeWindow(name string, defaultValue Window, opts ...Option) Window {
+	return std.TimeWindow(name, defaultValue, opts...)
+}
+
+// TimeWindow extracts Window value from variable named name
+// and returns defaultValue if it is absent or can not be parsed.
+// The value has form "22:00-06:00" optionally followed by a time zone name:
+// "22:00-06:00 Europe/Berlin". Local time zone is used if it is omitted
+func (e *Env) TimeWindow(name string, defaultValue Window, opts ...Option) Window {
+	w, err := e.TimeWindowStrict(name, defaultValue, opts...)
+	if err != nil {
+		e.fail(err)
+		return defaultValue
+	}
+
+	return w
+}
+
+// TimeWindowStrict extracts Window value from environment variable named name
+// and returns defaultValue if it is absent. If the environment variable
+// can not be parsed, the method returns an error
+func TimeWindowStrict(name string, defaultValue Window, opts ...Option) (Window, error) {
+	return std.TimeWindowStrict(name, defaultValue, opts...)
+}
+
+// TimeWindowStrict extracts Window value from variable named name
+// and returns defaultValue if it is absent. If the variable
+// can not be parsed, the method returns an error
+func (e *Env) TimeWindowStrict(name string, defaultValue Window, opts ...Option) (Window, error) {
+	if !e.registered([]string{name}) {
+		opts := append([]Option(nil), opts...)
+		e.register([]string{name}, func(env *Env) error {
+			_, err := env.TimeWindowStrict(name, defaultValue, opts...)
+			return err
+		})
+	}
+
+	name, strVal, ok, err := e.value([]string{name}, otherDef(defaultValue), newOptions(opts))
+	if err != nil {
+		return Window{}, err
+	}
+	if ok {
+		w, err := parseWindow(strVal)
+		if err != nil {
+			return Window{}, &ParseError{Var: name, Raw: strVal, Type: "defenv.Window", Err: err}
+		}
+
+		return w, nil
+	}
+
+	return defaultValue, nil
+}
+
+func parseWindow(s string) (Window, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return Window{}, fmt.Errorf("invalid time window %q", s)
+	}
+
+	bounds := strings.Split(fields[0], "-")
+	if len(bounds) != 2 {
+		return Window{}, fmt.Errorf("invalid time window %q", s)
+	}
+
+	start, err := parseClock(bounds[0])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid time window %q: %s", s, err)
+	}
+
+	end, err := parseClock(bounds[1])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid time window %q: %s", s, err)
+	}
+
+	loc := time.Local
+	if len(fields) == 2 {
+		if loc, err = time.LoadLocation(fields[1]); err != nil {
+			return Window{}, fmt.Errorf("invalid time window %q: %s", s, err)
+		}
+	}
+
+	return Window{Start: start, End: end, Location: loc}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
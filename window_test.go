@@ -0,0 +1,208 @@
+package defenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWindowContains(t *testing.T) {
+	night := Window{Start: 22 * time.Hour, End: 6 * time.Hour, Location: time.UTC}
+	day := Window{Start: 9 * time.Hour, End: 18 * time.Hour, Location: time.UTC}
+	whole := Window{Location: time.UTC}
+
+	for _, tc := range []struct {
+		name   string
+		window Window
+		t      time.Time
+		expRes bool
+	}{
+		{
+			name:   `true then 23:30 is in 22:00-06:00`,
+			window: night,
+			t:      time.Date(2018, 1, 1, 23, 30, 0, 0, time.UTC),
+			expRes: true,
+		},
+		{
+			name:   `true then 05:59 is in 22:00-06:00`,
+			window: night,
+			t:      time.Date(2018, 1, 1, 5, 59, 0, 0, time.UTC),
+			expRes: true,
+		},
+		{
+			name:   `false then 06:00 is in 22:00-06:00`,
+			window: night,
+			t:      time.Date(2018, 1, 1, 6, 0, 0, 0, time.UTC),
+			expRes: false,
+		},
+		{
+			name:   `true then 09:00 is in 09:00-18:00`,
+			window: day,
+			t:      time.Date(2018, 1, 1, 9, 0, 0, 0, time.UTC),
+			expRes: true,
+		},
+		{
+			name:   `false then 20:00 is in 09:00-18:00`,
+			window: day,
+			t:      time.Date(2018, 1, 1, 20, 0, 0, 0, time.UTC),
+			expRes: false,
+		},
+		{
+			name:   `false then 12:00 in UTC+03:00 is in 10:00-11:00 UTC`,
+			window: Window{Start: 10 * time.Hour, End: 11 * time.Hour, Location: time.UTC},
+			t:      time.Date(2018, 1, 1, 12, 0, 0, 0, time.FixedZone("", 3*3600)),
+			expRes: false,
+		},
+		{
+			name:   `true then start and end are equal`,
+			window: whole,
+			t:      time.Date(2018, 1, 1, 15, 0, 0, 0, time.UTC),
+			expRes: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.window.Contains(tc.t)
+			if res != tc.expRes {
+				t.Errorf("expected value: %t, got: %t", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestTimeWindow(t *testing.T) {
+	def := Window{Start: time.Hour, End: 2 * time.Hour, Location: time.UTC}
+
+	for _, tc := range []struct {
+		name         string
+		setEnv       bool
+		envValue     string
+		defaultValue Window
+		expRes       Window
+	}{
+		{
+			name:         `22:00-06:00 then environment value is "22:00-06:00 UTC"`,
+			setEnv:       true,
+			envValue:     "22:00-06:00 UTC",
+			defaultValue: def,
+			expRes:       Window{Start: 22 * time.Hour, End: 6 * time.Hour, Location: time.UTC},
+		},
+		{
+			name:         `use default value then environment value is "22:00"`,
+			setEnv:       true,
+			envValue:     "22:00",
+			defaultValue: def,
+			expRes:       def,
+		},
+		{
+			name:         `use default value then environment value is "bad"`,
+			setEnv:       true,
+			envValue:     "bad",
+			defaultValue: def,
+			expRes:       def,
+		},
+		{
+			name:         `use default value then environment value is not set`,
+			setEnv:       false,
+			defaultValue: def,
+			expRes:       def,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if err := os.Unsetenv("VALUE"); err != nil {
+					t.Errorf("coudn't unset VALUE: %s", err)
+				}
+			}()
+
+			if tc.setEnv {
+				if err := os.Setenv("VALUE", tc.envValue); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res := TimeWindow("VALUE", tc.defaultValue)
+			if res != tc.expRes {
+				t.Errorf("expected value: %v, got: %v", tc.expRes, res)
+			}
+		})
+	}
+}
+
+func TestTimeWindowStrict(t *testing.T) {
+	def := Window{Start: time.Hour, End: 2 * time.Hour, Location: time.UTC}
+
+	for _, tc := range []struct {
+		name         string
+		setEnv       bool
+		envValue     string
+		defaultValue Window
+		expRes       Window
+		expErr       error
+	}{
+		{
+			name:         `08:30-17:45 from environment as "08:30-17:45 UTC"`,
+			setEnv:       true,
+			envValue:     "08:30-17:45 UTC",
+			defaultValue: def,
+			expRes:       Window{Start: 8*time.Hour + 30*time.Minute, End: 17*time.Hour + 45*time.Minute, Location: time.UTC},
+		},
+		{
+			name:         `local time zone then environment value is "22:00-06:00"`,
+			setEnv:       true,
+			envValue:     "22:00-06:00",
+			defaultValue: def,
+			expRes:       Window{Start: 22 * time.Hour, End: 6 * time.Hour, Location: time.Local},
+		},
+		{
+			name:         `fail then environment value is "22:00-06:00 Nowhere/Bad"`,
+			setEnv:       true,
+			envValue:     "22:00-06:00 Nowhere/Bad",
+			defaultValue: def,
+			expErr:       errors.New(`defenv: parse VALUE="22:00-06:00 Nowhere/Bad" as defenv.Window: invalid time window "22:00-06:00 Nowhere/Bad": unknown time zone Nowhere/Bad`),
+		},
+		{
+			name:         `fail then environment value is "22:00-25:00"`,
+			setEnv:       true,
+			envValue:     "22:00-25:00",
+			defaultValue: def,
+			expErr:       errors.New(`defenv: parse VALUE="22:00-25:00" as defenv.Window: invalid time window "22:00-25:00": parsing time "25:00": hour out of range`),
+		},
+		{
+			name:         `fail then environment value is ""`,
+			setEnv:       true,
+			envValue:     "",
+			defaultValue: def,
+			expErr:       errors.New(`defenv: parse VALUE="" as defenv.Window: invalid time window ""`),
+		},
+		{
+			name:         `use default value then environment value is not set`,
+			setEnv:       false,
+			defaultValue: def,
+			expRes:       def,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if err := os.Unsetenv("VALUE"); err != nil {
+					t.Errorf("coudn't unset VALUE: %s", err)
+				}
+			}()
+
+			if tc.setEnv {
+				if err := os.Setenv("VALUE", tc.envValue); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res, err := TimeWindowStrict("VALUE", tc.defaultValue)
+			if fmt.Sprint(err) != fmt.Sprint(tc.expErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expErr, err)
+			}
+			if res != tc.expRes {
+				t.Errorf("expected value: %v, got: %v", tc.expRes, res)
+			}
+		})
+	}
+}